@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/dropseed/deps/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var checkTypes []string
+var checkMaxPending int
+var checkMaxAges []string
+var checkFormat string
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Show outdated dependencies without updating them",
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := runner.ParsePolicy(checkMaxPending, checkMaxAges)
+		if err != nil {
+			printErrAndExitFailure(err)
+		}
+
+		if err := runner.Check(checkTypes, policy, checkFormat); err != nil {
+			printErrAndExitFailure(err)
+		}
+	},
+}
+
+func init() {
+	checkCmd.Flags().StringArrayVarP(&checkTypes, "type", "t", []string{}, "only check specified dependency types")
+	checkCmd.Flags().IntVar(&checkMaxPending, "max-pending", -1, "fail if more than this many updates are pending")
+	checkCmd.Flags().StringArrayVar(&checkMaxAges, "max-age", []string{}, "fail if an update type was released more than this many days ago (ex. major=90)")
+	checkCmd.Flags().StringVarP(&checkFormat, "format", "f", runner.FormatTable, "output format (table, json, markdown or sarif)")
+	rootCmd.AddCommand(checkCmd)
+}
@@ -19,6 +19,10 @@ var ciCMD = &cobra.Command{
 			output.Verbosity = 1
 		}
 
+		if runner.DryRunAct {
+			runner.DryRun = true
+		}
+
 		auto := !ciManual
 		if err := runner.CI(auto, ciTypes); err != nil {
 			printErrAndExitFailure(err)
@@ -30,5 +34,10 @@ func init() {
 	ciCMD.Flags().BoolVarP(&ciManual, "manual", "m", false, "do not automatically configure repo")
 	ciCMD.Flags().BoolVarP(&ciQuiet, "quiet", "q", false, "disable verbose output")
 	ciCMD.Flags().StringArrayVarP(&ciTypes, "type", "t", []string{}, "only run on specified dependency types")
+	// Set these variables directly in the runner module
+	ciCMD.Flags().BoolVar(&runner.DryRun, "dry-run", false, "show the planned updates without pushing or opening pull requests")
+	ciCMD.Flags().BoolVar(&runner.DryRunAct, "dry-run-act", false, "with --dry-run, also make the updates in a temporary worktree and show the diffs")
+	ciCMD.Flags().BoolVar(&runner.Worktrees, "worktrees", false, "make each update in a separate git worktree, --jobs at a time")
+	ciCMD.Flags().StringVar(&runner.ReportPath, "report", "", "write a JSON report of the updates to this path")
 	rootCmd.AddCommand(ciCMD)
 }
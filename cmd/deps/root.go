@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/internal/runner"
 	"github.com/dropseed/deps/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -20,4 +21,5 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().IntVarP(&runner.Jobs, "jobs", "j", 1, "number of dependency entries to collect at once")
 }
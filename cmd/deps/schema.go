@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dropseed/deps/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON schema for component output",
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := json.MarshalIndent(schema.NewJSONSchema(), "", "  ")
+		if err != nil {
+			printErrAndExitFailure(err)
+		}
+		fmt.Println(string(out))
+	},
+}
+
+func init() {
+	devCmd.AddCommand(schemaCmd)
+}
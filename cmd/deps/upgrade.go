@@ -5,12 +5,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var upgradeSelection = &runner.Selection{}
+
 var upgradeCmd = &cobra.Command{
 	Use:     "upgrade",
 	Aliases: []string{"update"},
 	Short:   "Locally upgrade deps in the current directory",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := runner.Local(); err != nil {
+		if err := runner.Local(upgradeSelection); err != nil {
 			printErrAndExitFailure(err)
 		}
 	},
@@ -18,4 +20,12 @@ var upgradeCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(upgradeCmd)
+	// Set this variable directly in the runner module
+	upgradeCmd.Flags().StringVar(&runner.ReportPath, "report", "", "write a JSON report of the updates to this path")
+	// Any of these will skip the prompt
+	upgradeCmd.Flags().BoolVarP(&upgradeSelection.All, "all", "a", false, "make all of the updates without prompting")
+	upgradeCmd.Flags().StringArrayVar(&upgradeSelection.IDs, "id", []string{}, "make the update with this id")
+	upgradeCmd.Flags().StringArrayVarP(&upgradeSelection.Names, "name", "n", []string{}, "make updates to dependencies matching this pattern")
+	upgradeCmd.Flags().StringArrayVarP(&upgradeSelection.Types, "type", "t", []string{}, "make updates for this dependency type")
+	upgradeCmd.Flags().StringArrayVarP(&upgradeSelection.Paths, "path", "p", []string{}, "make updates in this manifest, lockfile or directory")
 }
@@ -26,9 +26,12 @@ func (circle *CircleCI) Autoconfigure() error {
 		cmd.Run() // Don't worry about an error
 	}
 
-	originalOrigin := git.GitRemote()
+	originalOrigin, err := git.PushRemote()
+	if err != nil {
+		return err
+	}
 	if updatedOrigin := git.GitRemoteToHTTPS(originalOrigin); originalOrigin != updatedOrigin {
-		if cmd := exec.Command("git", "remote", "set-url", "origin", updatedOrigin); cmd != nil {
+		if cmd := exec.Command("git", "remote", "set-url", git.RemoteName(), updatedOrigin); cmd != nil {
 			output.Event("Autoconfigure: %s", strings.Join(cmd.Args, " "))
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
@@ -2,8 +2,6 @@ package ci
 
 import (
 	"os"
-	"os/exec"
-	"strings"
 
 	"github.com/dropseed/deps/internal/ci/bitbucketpipelines"
 	"github.com/dropseed/deps/internal/ci/circleci"
@@ -11,6 +9,7 @@ import (
 	"github.com/dropseed/deps/internal/ci/githubactions"
 	"github.com/dropseed/deps/internal/ci/gitlabci"
 	"github.com/dropseed/deps/internal/ci/travisci"
+	"github.com/dropseed/deps/internal/git"
 	"github.com/dropseed/deps/internal/output"
 )
 
@@ -38,8 +37,8 @@ func NewCIProvider() CIProvider {
 	return &generic.GenericCI{}
 }
 
-func BaseAutoconfigure() {
-
+// GitUser is the name and email that deps commits with
+func GitUser() (string, string) {
 	gitName := "deps"
 	gitEmail := "bot@dependencies.io"
 
@@ -50,21 +49,14 @@ func BaseAutoconfigure() {
 		gitEmail = s
 	}
 
-	if cmd := exec.Command("git", "config", "user.name", gitName); cmd != nil {
-		output.Event("Autoconfigure: %s", strings.Join(cmd.Args, " "))
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			panic(err)
-		}
-	}
+	return gitName, gitEmail
+}
 
-	if cmd := exec.Command("git", "config", "user.email", gitEmail); cmd != nil {
-		output.Event("Autoconfigure: %s", strings.Join(cmd.Args, " "))
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			panic(err)
-		}
-	}
+func BaseAutoconfigure() error {
+
+	gitName, gitEmail := GitUser()
+
+	output.Event("Autoconfigure: git config user.name %s", gitName)
+	output.Event("Autoconfigure: git config user.email %s", gitEmail)
+	return git.SetUser(gitName, gitEmail)
 }
@@ -10,7 +10,7 @@ import (
 )
 
 func (r *Runner) Act(inputDependencies *schema.Dependencies) (*schema.Dependencies, error) {
-	output.Event("Updating with %s", r.Given)
+	r.Logger.Event("Updating with %s", r.Given)
 
 	inputFilename, err := inputTempFile(inputDependencies)
 	if err != nil {
@@ -35,11 +35,19 @@ func (r *Runner) Act(inputDependencies *schema.Dependencies) (*schema.Dependenci
 		return nil, err
 	}
 
+	for _, warning := range outputDependencies.Warnings {
+		r.Logger.Warning("%s", warning)
+	}
+
 	return outputDependencies, nil
 }
 
 func inputTempFile(inputDependencies *schema.Dependencies) (string, error) {
-	inputJSON, err := json.MarshalIndent(inputDependencies, "", "  ")
+	// Always tell the component which version of the schema it is getting
+	versioned := *inputDependencies
+	versioned.SchemaVersion = schema.CurrentSchemaVersion
+
+	inputJSON, err := json.MarshalIndent(versioned, "", "  ")
 	if err != nil {
 		return "", err
 	}
@@ -9,11 +9,11 @@ import (
 
 func (r *Runner) Collect(inputPath string) (*schema.Dependencies, error) {
 	if output.Verbosity > 0 {
-		output.Event("Collecting with %s", r.Given)
+		r.Logger.Event("Collecting with %s", r.Given)
 	} else {
-		output.Event("Collecting with %s", r.GetName())
+		r.Logger.Event("Collecting with %s", r.GetName())
 	}
-	output.Debug("Input path: %s", inputPath)
+	r.Logger.Debug("Input path: %s", inputPath)
 
 	outputPath, err := r.run(r.getCommand(r.Config.Collect, "collect"), inputPath)
 	if err != nil {
@@ -23,13 +23,17 @@ func (r *Runner) Collect(inputPath string) (*schema.Dependencies, error) {
 		defer os.Remove(outputPath)
 	}
 
-	output.Debug("Finished")
+	r.Logger.Debug("Finished")
 
 	dependencies, err := schema.NewDependenciesFromJSONPath(outputPath)
 	if err != nil {
-		output.Error("Unable to load output JSON from collector")
+		r.Logger.Error("Unable to load output JSON from collector")
 		return nil, err
 	}
 
+	for _, warning := range dependencies.Warnings {
+		r.Logger.Warning("%s", warning)
+	}
+
 	return dependencies, nil
 }
@@ -9,21 +9,21 @@ import (
 
 func (r *Runner) Install() error {
 	if !r.shouldInstall {
-		output.Debug("Skipping install of %s", r.Given)
+		r.Logger.Debug("Skipping install of %s", r.Given)
 		return nil
 	}
 
-	output.Event("Installing %s", r.Given)
+	r.Logger.Event("Installing %s", r.Given)
 
 	command := r.getCommand(r.Config.Install, "install")
-	output.Debug(command)
+	r.Logger.Debug(command)
 
 	cmd := exec.Command("sh", "-c", command)
 	cmd.Dir = r.Path
 	cmd.Env = append(os.Environ(), r.Env...)
 	if output.IsDebug() {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		cmd.Stdout = r.Logger.Output()
+		cmd.Stderr = r.Logger.Output()
 	}
 	err := cmd.Run()
 	if err != nil {
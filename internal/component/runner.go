@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/dropseed/deps/internal/config"
@@ -15,11 +16,15 @@ import (
 )
 
 type Runner struct {
-	Index         int
-	Given         string
-	Config        *Config
-	Path          string
-	Env           []string
+	Index  int
+	Given  string
+	Config *Config
+	Path   string
+	Env    []string
+	// Dir is where the commands run (the current directory if empty)
+	Dir string
+	// Logger buffers the output when running concurrently (printed directly if nil)
+	Logger        *output.Logger
 	shouldInstall bool
 }
 
@@ -99,7 +104,9 @@ func newRunnerFromRemote(s string) (*Runner, error) {
 	}
 
 	if _, err := os.Stat(clonePath); os.IsNotExist(err) {
-		git.Clone(url, clonePath)
+		if err := git.Clone(url, clonePath); err != nil {
+			return nil, err
+		}
 		cloned = true
 	} else if err != nil {
 		return nil, err
@@ -113,7 +120,9 @@ func newRunnerFromRemote(s string) (*Runner, error) {
 	refBefore := ""
 
 	if !cloned {
-		refBefore = git.CurrentRef()
+		if refBefore, err = git.CurrentRef(); err != nil {
+			return nil, err
+		}
 		if err := git.Pull(); err != nil {
 			return nil, err
 		}
@@ -123,7 +132,10 @@ func newRunnerFromRemote(s string) (*Runner, error) {
 	// split @ from string?
 	// git.Checkout
 
-	refAfter := git.CurrentRef()
+	refAfter, err := git.CurrentRef()
+	if err != nil {
+		return nil, err
+	}
 
 	if err := os.Chdir(cwd); err != nil {
 		panic(err)
@@ -154,7 +166,7 @@ func (r *Runner) getCommand(defaultCmd, cmdName string) string {
 func (r *Runner) getOverrideFromEnv(name string) string {
 	override := os.Getenv(fmt.Sprintf("DEPS_%d_%s", r.Index, strings.ToUpper(name)))
 	if override != "" {
-		output.Event("Overriding %s command from env", name)
+		r.Logger.Event("Overriding %s command from env", name)
 	}
 	return override
 }
@@ -168,7 +180,7 @@ func (r *Runner) run(command string, inputPath string) (string, error) {
 
 	commandString := fmt.Sprintf("%s %s %s", command, inputPath, outputPath)
 
-	output.Debug(commandString)
+	r.Logger.Debug(commandString)
 
 	cmd := exec.Command(
 		"sh",
@@ -178,12 +190,21 @@ func (r *Runner) run(command string, inputPath string) (string, error) {
 
 	if output.IsDebug() {
 		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		cmd.Stdout = r.Logger.Output()
+		cmd.Stderr = r.Logger.Output()
+	}
+
+	componentPath := r.Path
+	if r.Dir != "" {
+		// the component path could be relative to the current directory
+		cmd.Dir = r.Dir
+		if componentPath, err = filepath.Abs(componentPath); err != nil {
+			return "", err
+		}
 	}
 
 	cmd.Env = r.Env
-	cmd.Env = append(cmd.Env, fmt.Sprintf("DEPS_COMPONENT_PATH=%s", r.Path))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("DEPS_COMPONENT_PATH=%s", componentPath))
 	if err != nil {
 		return "", err
 	}
@@ -193,7 +214,7 @@ func (r *Runner) run(command string, inputPath string) (string, error) {
 		return "", err
 	}
 
-	output.Debug(outputPath)
+	r.Logger.Debug(outputPath)
 
 	return outputPath, nil
 }
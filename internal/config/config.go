@@ -21,13 +21,45 @@ var DefaultFilenames = []string{
 
 const Version = 3
 
+// GroupingAll combines every update into a single update
+const GroupingAll = "all"
+
+// How the branches for outdated updates are brought up to date
+const (
+	// OutdatedBranchesUpdate makes the update again on top of the existing branch
+	OutdatedBranchesUpdate = "update"
+	// OutdatedBranchesRecreate makes the update on a fresh branch from the base and force pushes it
+	OutdatedBranchesRecreate = "recreate"
+	// OutdatedBranchesRebase rebases the existing branch onto the base before making the update
+	OutdatedBranchesRebase = "rebase"
+)
+
 // Config stores a dependencies.yml config
 type Config struct {
 	Version      int           `mapstructure:"version" yaml:"version" json:"version"`
 	Dependencies []*Dependency `mapstructure:"dependencies" yaml:"dependencies" json:"dependencies"`
+	// Grouping can be "all" to send every update (across all types) as one update
+	Grouping string `mapstructure:"grouping,omitempty" yaml:"grouping,omitempty" json:"grouping,omitempty"`
+	// MaxOpenUpdates limits the total number of deps branches (0 is unlimited)
+	MaxOpenUpdates int `mapstructure:"max_open_updates,omitempty" yaml:"max_open_updates,omitempty" json:"max_open_updates,omitempty"`
+	// MaxNewUpdatesPerRun limits how many new updates are created at once (0 is unlimited)
+	MaxNewUpdatesPerRun int `mapstructure:"max_new_updates_per_run,omitempty" yaml:"max_new_updates_per_run,omitempty" json:"max_new_updates_per_run,omitempty"`
+	// Git changes the remote and how deps branches are named
+	Git Git `mapstructure:"git,omitempty" yaml:"git,omitempty" json:"git,omitempty"`
+	// OutdatedBranches is "update" (default), "recreate" or "rebase"
+	OutdatedBranches string `mapstructure:"outdated_branches,omitempty" yaml:"outdated_branches,omitempty" json:"outdated_branches,omitempty"`
+	// CloseStaleUpdates closes pull requests (and deletes branches) for updates that are no longer needed
+	CloseStaleUpdates *bool `mapstructure:"close_stale_updates,omitempty" yaml:"close_stale_updates,omitempty" json:"close_stale_updates,omitempty"`
 }
 
 func (config *Config) Compile() {
+	if config.OutdatedBranches == "" {
+		config.OutdatedBranches = OutdatedBranchesUpdate
+	}
+	if config.CloseStaleUpdates == nil {
+		t := true
+		config.CloseStaleUpdates = &t
+	}
 	for _, dependency := range config.Dependencies {
 		dependency.Compile()
 	}
@@ -90,6 +122,28 @@ func newConfigFromMap(m map[string]interface{}) (*Config, error) {
 		return nil, fmt.Errorf("Config must be version %d", Version)
 	}
 
+	if config.Grouping != "" && config.Grouping != GroupingAll {
+		return nil, fmt.Errorf("Unknown grouping \"%s\", must be \"%s\"", config.Grouping, GroupingAll)
+	}
+
+	switch config.OutdatedBranches {
+	case "", OutdatedBranchesUpdate, OutdatedBranchesRecreate, OutdatedBranchesRebase:
+	default:
+		return nil, fmt.Errorf("Unknown outdated_branches \"%s\", must be \"%s\", \"%s\" or \"%s\"", config.OutdatedBranches, OutdatedBranchesUpdate, OutdatedBranchesRecreate, OutdatedBranchesRebase)
+	}
+
+	if config.MaxOpenUpdates < 0 || config.MaxNewUpdatesPerRun < 0 {
+		return nil, fmt.Errorf("max_open_updates and max_new_updates_per_run can't be negative")
+	}
+
+	for _, dependency := range config.Dependencies {
+		if dependency.Schedule != nil {
+			if err := dependency.Schedule.Validate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return config, nil
 }
 
@@ -19,6 +19,8 @@ type Dependency struct {
 	Settings        Settings          `mapstructure:"settings,omitempty" yaml:"settings,omitempty" json:"settings"`
 	LockfileUpdates LockfileUpdates   `mapstructure:"lockfile_updates,omitempty" yaml:"lockfile_updates,omitempty" json:"lockfile_updates,omitempty"`
 	ManifestUpdates ManifestUpdates   `mapstructure:"manifest_updates,omitempty" yaml:"manifest_updates,omitempty" json:"manifest_updates,omitempty"`
+	Ignore          []*Ignore         `mapstructure:"ignore,omitempty" yaml:"ignore,omitempty" json:"ignore,omitempty"`
+	Schedule        *Schedule         `mapstructure:"schedule,omitempty" yaml:"schedule,omitempty" json:"schedule,omitempty"`
 }
 
 func (dependency *Dependency) Compile() {
@@ -53,7 +55,12 @@ func (dependency *Dependency) Compile() {
 		}
 		dependency.ManifestUpdates.Filters = append(dependency.ManifestUpdates.Filters, defaultFilter)
 	}
-	for _, filter := range dependency.ManifestUpdates.Filters {
+	compileFilters(dependency.ManifestUpdates.Filters)
+	compileFilters(dependency.LockfileUpdates.Filters)
+}
+
+func compileFilters(filters []*Filter) {
+	for _, filter := range filters {
 		if filter.Enabled == nil {
 			t := true
 			filter.Enabled = &t
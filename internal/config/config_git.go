@@ -0,0 +1,11 @@
+package config
+
+// Git changes where deps pushes its branches and how they are named
+type Git struct {
+	// Remote to push to and look for existing branches on (default "origin")
+	Remote string `mapstructure:"remote,omitempty" yaml:"remote,omitempty" json:"remote,omitempty"`
+	// BranchPrefix goes before "deps" in branch names
+	BranchPrefix string `mapstructure:"branch_prefix,omitempty" yaml:"branch_prefix,omitempty" json:"branch_prefix,omitempty"`
+	// BranchSeparator goes after "deps" in branch names (default "/")
+	BranchSeparator string `mapstructure:"branch_separator,omitempty" yaml:"branch_separator,omitempty" json:"branch_separator,omitempty"`
+}
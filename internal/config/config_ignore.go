@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/dropseed/deps/pkg/schema"
+	"github.com/dropseed/deps/pkg/versioning"
+)
+
+const ignoreUntilLayout = "2006-01-02"
+
+// Ignore skips updates to specific dependency versions
+type Ignore struct {
+	// Name is a pattern for the dependency name
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	// Version is a version or range to skip (all versions if empty)
+	Version string `mapstructure:"version,omitempty" yaml:"version,omitempty" json:"version,omitempty"`
+	// Until is a date (YYYY-MM-DD) when the ignore expires
+	Until  string `mapstructure:"until,omitempty" yaml:"until,omitempty" json:"until,omitempty"`
+	Reason string `mapstructure:"reason,omitempty" yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+func (ignore *Ignore) Validate() error {
+	if ignore.Name == "" {
+		return fmt.Errorf("Ignore name is required")
+	}
+	if _, err := regexp.Compile(ignore.Name); err != nil {
+		return fmt.Errorf("Ignore \"%s\" has an invalid name pattern: %v", ignore.Name, err)
+	}
+	if ignore.Version != "" {
+		if _, err := versioning.ParseRange(ignore.Version); err != nil {
+			return fmt.Errorf("Ignore \"%s\" has an invalid version: %v", ignore.Name, err)
+		}
+	}
+	if ignore.Until != "" {
+		if _, err := time.ParseInLocation(ignoreUntilLayout, ignore.Until, time.Local); err != nil {
+			return fmt.Errorf("Ignore \"%s\" has an invalid until date (use YYYY-MM-DD): %v", ignore.Name, err)
+		}
+	}
+	return nil
+}
+
+// IsActive checks whether the until date has passed
+func (ignore *Ignore) IsActive(now time.Time) bool {
+	if ignore.Until == "" {
+		return true
+	}
+	until, err := time.ParseInLocation(ignoreUntilLayout, ignore.Until, time.Local)
+	if err != nil {
+		return false
+	}
+	return now.Before(until)
+}
+
+// Matches checks the dependency name and the version it would be updated to
+func (ignore *Ignore) Matches(name string, version *versioning.Version) bool {
+	if !regexp.MustCompile(ignore.Name).MatchString(name) {
+		return false
+	}
+	if ignore.Version == "" {
+		return true
+	}
+	if version == nil {
+		return false
+	}
+	r, err := versioning.ParseRange(ignore.Version)
+	if err != nil {
+		return false
+	}
+	return r.Contains(version)
+}
+
+// Description explains why an update is being ignored
+func (ignore *Ignore) Description() string {
+	s := ignore.Reason
+	if s == "" {
+		s = "no reason given"
+	}
+	if ignore.Until != "" {
+		s = fmt.Sprintf("%s (until %s)", s, ignore.Until)
+	}
+	return s
+}
+
+// IgnoreForManifestDependency finds the first active ignore for an updated manifest dependency
+func (dependency *Dependency) IgnoreForManifestDependency(name string, dep *schema.ManifestDependency, now time.Time) (*Ignore, error) {
+	return dependency.ignoreFor(name, updatedManifestVersion(dep), now)
+}
+
+// IgnoreForLockfileDependency finds the first active ignore for an updated lockfile dependency
+func (dependency *Dependency) IgnoreForLockfileDependency(name string, dep *schema.LockfileDependency, now time.Time) (*Ignore, error) {
+	var version *versioning.Version
+	if v, err := versioning.Parse(dep.Version.Name); err == nil {
+		version = v
+	}
+	return dependency.ignoreFor(name, version, now)
+}
+
+func (dependency *Dependency) ignoreFor(name string, version *versioning.Version, now time.Time) (*Ignore, error) {
+	for _, ignore := range dependency.Ignore {
+		if err := ignore.Validate(); err != nil {
+			return nil, err
+		}
+		if ignore.IsActive(now) && ignore.Matches(name, version) {
+			return ignore, nil
+		}
+	}
+	return nil, nil
+}
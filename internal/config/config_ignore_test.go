@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+func TestIgnoreVersion(t *testing.T) {
+	dep := &Dependency{
+		Ignore: []*Ignore{
+			{Name: "^lodash$", Version: "4.17.99", Reason: "broken release"},
+		},
+	}
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local)
+
+	broken := &schema.ManifestDependency{Constraint: "^4.17.99"}
+	ignore, err := dep.IgnoreForManifestDependency("lodash", broken, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ignore == nil || ignore.Description() != "broken release" {
+		t.Error("lodash 4.17.99 should be ignored")
+	}
+
+	fixed := &schema.ManifestDependency{Constraint: "^4.17.100"}
+	if ignore, _ := dep.IgnoreForManifestDependency("lodash", fixed, now); ignore != nil {
+		t.Error("newer lodash should not be ignored")
+	}
+
+	if ignore, _ := dep.IgnoreForManifestDependency("lodash.merge", broken, now); ignore != nil {
+		t.Error("other dependency should not be ignored")
+	}
+}
+
+func TestIgnoreUntil(t *testing.T) {
+	ignore := &Ignore{Name: "react", Until: "2020-06-01"}
+	if err := ignore.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !ignore.IsActive(time.Date(2020, 5, 31, 23, 0, 0, 0, time.Local)) {
+		t.Error("should be active before the date")
+	}
+	if ignore.IsActive(time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local)) {
+		t.Error("should expire on the date")
+	}
+	if ignore.Description() != "no reason given (until 2020-06-01)" {
+		t.Error(ignore.Description())
+	}
+}
+
+func TestIgnoreInvalid(t *testing.T) {
+	if err := (&Ignore{Name: "react", Until: "June"}).Validate(); err == nil {
+		t.Error("expected an invalid date")
+	}
+	if err := (&Ignore{Name: "react", Version: ">=banana"}).Validate(); err == nil {
+		t.Error("expected an invalid version")
+	}
+}
@@ -1,6 +1,150 @@
 package config
 
+import (
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/pkg/schema"
+	"github.com/dropseed/deps/pkg/versioning"
+)
+
+// Names for the lockfile updates that are created when Split is enabled
+const (
+	LockfileDirect     = "direct"
+	LockfileTransitive = "transitive"
+)
+
 type LockfileUpdates struct {
-	Enabled  *bool    `mapstructure:"enabled,omitempty" yaml:"enabled,omitempty" json:"enabled,omitempty"`
-	Settings Settings `mapstructure:"settings,omitempty" yaml:"settings,omitempty" json:"settings"`
+	Enabled  *bool     `mapstructure:"enabled,omitempty" yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Settings Settings  `mapstructure:"settings,omitempty" yaml:"settings,omitempty" json:"settings"`
+	Filters  []*Filter `mapstructure:"filters,omitempty" yaml:"filters,omitempty" json:"filters,omitempty"`
+	// Split direct dependency updates and transitive-only updates into separate updates
+	Split bool `mapstructure:"split,omitempty" yaml:"split,omitempty" json:"split,omitempty"`
+}
+
+// FilteredLockfiles returns copies of the lockfile that only include the
+// changes allowed by the filters, leaving the ignored dependencies at their
+// current versions. The result is keyed by "direct" and "transitive" when
+// the updates are split, otherwise by an empty string.
+func (lockfileUpdates *LockfileUpdates) FilteredLockfiles(lockfile *schema.Lockfile, ignored map[string]bool) (map[string]*schema.Lockfile, error) {
+	for _, filter := range lockfileUpdates.Filters {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := map[string]map[string]bool{}
+
+	for name, dep := range lockfile.Updated.Dependencies {
+		if current, found := lockfile.Current.Dependencies[name]; found && current.Version.Name == dep.Version.Name {
+			// not a change
+			continue
+		}
+
+		if ignored[name] {
+			continue
+		}
+
+		enabled, err := lockfileUpdates.enabledForDependency(name, lockfile)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		groupName := ""
+		if lockfileUpdates.Split {
+			groupName = LockfileDirect
+			if dep.IsTransitive {
+				groupName = LockfileTransitive
+			}
+		}
+
+		if groups[groupName] == nil {
+			groups[groupName] = map[string]bool{}
+		}
+		groups[groupName][name] = true
+	}
+
+	// removed dependencies can't be filtered by version,
+	// so they go along with the rest of the refresh
+	removed := []string{}
+	for name := range lockfile.Current.Dependencies {
+		if _, found := lockfile.Updated.Dependencies[name]; !found {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		groupName := ""
+		if lockfileUpdates.Split {
+			groupName = LockfileTransitive
+		}
+		if groups[groupName] == nil {
+			groups[groupName] = map[string]bool{}
+		}
+		for _, name := range removed {
+			groups[groupName][name] = true
+		}
+	}
+
+	filtered := map[string]*schema.Lockfile{}
+
+	for groupName, names := range groups {
+		updated := &schema.LockfileVersion{
+			// The fingerprint still identifies the lockfile that
+			// the component collected, even if only part of it is used
+			Fingerprint:  lockfile.Updated.Fingerprint,
+			Dependencies: map[string]*schema.LockfileDependency{},
+		}
+		for name, dep := range lockfile.Current.Dependencies {
+			updated.Dependencies[name] = dep
+		}
+		for name := range names {
+			if dep, found := lockfile.Updated.Dependencies[name]; found {
+				updated.Dependencies[name] = dep
+			} else {
+				delete(updated.Dependencies, name)
+			}
+		}
+		filtered[groupName] = &schema.Lockfile{
+			Current: lockfile.Current,
+			Updated: updated,
+		}
+	}
+
+	return filtered, nil
+}
+
+func (lockfileUpdates *LockfileUpdates) enabledForDependency(name string, lockfile *schema.Lockfile) (bool, error) {
+	for _, filter := range lockfileUpdates.Filters {
+		if !filter.MatchesLockfileDependency(name, lockfile) {
+			continue
+		}
+
+		// the first filter that matches decides
+		if !*filter.Enabled {
+			return false, nil
+		}
+
+		excluded, err := filter.excludesVersion(updatedLockfileVersion(lockfile.Updated.Dependencies[name]))
+		if err != nil {
+			return false, err
+		}
+		if excluded {
+			output.Debug("Skipping %s update to %s because of filter \"%s\"", name, lockfile.Updated.Dependencies[name].Version.Name, filter.Name)
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	// everything is enabled if there are no filters,
+	// otherwise a dependency has to match one of them
+	return len(lockfileUpdates.Filters) == 0, nil
+}
+
+func updatedLockfileVersion(dep *schema.LockfileDependency) *versioning.Version {
+	if v, err := versioning.Parse(dep.Version.Name); err == nil {
+		return v
+	}
+	return nil
 }
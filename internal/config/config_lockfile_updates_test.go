@@ -0,0 +1,142 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+func testLockfile() *schema.Lockfile {
+	dependencies := map[string][2]string{
+		"react":        {"16.8.0", "17.0.0"},
+		"lodash":       {"4.17.15", "4.17.19"},
+		"left-pad":     {"1.1.0", "1.1.0"},
+		"js-tokens":    {"4.0.0", "4.0.1"},
+		"loose-envify": {"1.4.0", ""},
+	}
+	transitive := map[string]bool{
+		"js-tokens":    true,
+		"loose-envify": true,
+	}
+	lockfile := &schema.Lockfile{
+		Current: &schema.LockfileVersion{Fingerprint: "a", Dependencies: map[string]*schema.LockfileDependency{}},
+		Updated: &schema.LockfileVersion{Fingerprint: "b", Dependencies: map[string]*schema.LockfileDependency{}},
+	}
+	for name, versions := range dependencies {
+		source := &schema.Dependency{Source: "npm"}
+		lockfile.Current.Dependencies[name] = &schema.LockfileDependency{Version: &schema.Version{Name: versions[0]}, IsTransitive: transitive[name], Dependency: source}
+		if versions[1] != "" {
+			lockfile.Updated.Dependencies[name] = &schema.LockfileDependency{Version: &schema.Version{Name: versions[1]}, IsTransitive: transitive[name], Dependency: source}
+		}
+	}
+	return lockfile
+}
+
+func lockfilesFromYAML(t *testing.T, content string) map[string]*schema.Lockfile {
+	return ignoredLockfilesFromYAML(t, content, nil)
+}
+
+func ignoredLockfilesFromYAML(t *testing.T, content string, ignored map[string]bool) map[string]*schema.Lockfile {
+	cfg, err := NewConfigFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Compile()
+	lockfiles, err := cfg.Dependencies[0].LockfileUpdates.FilteredLockfiles(testLockfile(), ignored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lockfiles
+}
+
+func TestLockfileUpdatesUnfiltered(t *testing.T) {
+	lockfiles := lockfilesFromYAML(t, `version: 3
+dependencies:
+- type: js
+`)
+	if len(lockfiles) != 1 {
+		t.Fatal(lockfiles)
+	}
+	updated := lockfiles[""].Updated
+	if updated.Dependencies["react"].Version.Name != "17.0.0" || updated.Dependencies["js-tokens"].Version.Name != "4.0.1" {
+		t.Error("all changes should be included")
+	}
+	if _, found := updated.Dependencies["loose-envify"]; found {
+		t.Error("removed dependencies should stay removed")
+	}
+}
+
+func TestLockfileUpdatesFilters(t *testing.T) {
+	lockfiles := lockfilesFromYAML(t, `version: 3
+dependencies:
+- type: js
+  lockfile_updates:
+    filters:
+    - name: react
+      update_types: [major]
+      enabled: false
+    - name: .*
+      exclude_versions: ["4.0.1"]
+`)
+	updated := lockfiles[""].Updated
+	if updated.Dependencies["react"].Version.Name != "16.8.0" {
+		t.Error("react major should be disabled")
+	}
+	if updated.Dependencies["js-tokens"].Version.Name != "4.0.0" {
+		t.Error("js-tokens 4.0.1 should be excluded")
+	}
+	if updated.Dependencies["lodash"].Version.Name != "4.17.19" {
+		t.Error("lodash should be updated")
+	}
+}
+
+func TestLockfileUpdatesSplit(t *testing.T) {
+	lockfiles := lockfilesFromYAML(t, `version: 3
+dependencies:
+- type: js
+  lockfile_updates:
+    split: true
+`)
+	if len(lockfiles) != 2 {
+		t.Fatal(lockfiles)
+	}
+
+	direct := lockfiles[LockfileDirect].Updated
+	if direct.Dependencies["react"].Version.Name != "17.0.0" || direct.Dependencies["js-tokens"].Version.Name != "4.0.0" {
+		t.Error("direct update should only change direct dependencies")
+	}
+	if _, found := direct.Dependencies["loose-envify"]; !found {
+		t.Error("direct update should not remove transitive dependencies")
+	}
+
+	transitive := lockfiles[LockfileTransitive].Updated
+	if transitive.Dependencies["react"].Version.Name != "16.8.0" || transitive.Dependencies["js-tokens"].Version.Name != "4.0.1" {
+		t.Error("transitive update should only change transitive dependencies")
+	}
+	if _, found := transitive.Dependencies["loose-envify"]; found {
+		t.Error("transitive update should include removals")
+	}
+}
+
+func TestLockfileUpdatesIgnored(t *testing.T) {
+	lockfiles := ignoredLockfilesFromYAML(t, `version: 3
+dependencies:
+- type: js
+  lockfile_updates:
+    split: true
+`, map[string]bool{"js-tokens": true})
+
+	direct := lockfiles[LockfileDirect].Updated
+	if direct.Dependencies["react"].Version.Name != "17.0.0" {
+		t.Error("an ignored transitive dependency should not stop the direct update")
+	}
+
+	transitive := lockfiles[LockfileTransitive].Updated
+	if transitive.Dependencies["js-tokens"].Version.Name != "4.0.0" {
+		t.Error("ignored dependency should stay at its current version")
+	}
+	if _, found := transitive.Dependencies["loose-envify"]; found {
+		t.Error("transitive update should still include removals")
+	}
+}
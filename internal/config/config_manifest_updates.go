@@ -1,9 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/dropseed/deps/internal/output"
 	"github.com/dropseed/deps/pkg/schema"
+	"github.com/dropseed/deps/pkg/versioning"
 )
 
 type ManifestUpdates struct {
@@ -16,52 +20,185 @@ type Filter struct {
 	Name    string `mapstructure:"name" yaml:"name" json:"name"`
 	Enabled *bool  `mapstructure:"enabled,omitempty" yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	Group   *bool  `mapstructure:"group,omitempty" yaml:"group,omitempty" json:"group,omitempty"`
+	// GroupName combines matching updates from every manifest (and every
+	// dependency entry of the same type) into a single update
+	GroupName string `mapstructure:"group_name,omitempty" yaml:"group_name,omitempty" json:"group_name,omitempty"`
+	// UpdateTypes limits the filter to major, minor, patch, prerelease or unknown updates
+	UpdateTypes []string `mapstructure:"update_types,omitempty" yaml:"update_types,omitempty" json:"update_types,omitempty"`
+	// Source is a pattern for the dependency source (npm, pypi, etc.)
+	Source string `mapstructure:"source,omitempty" yaml:"source,omitempty" json:"source,omitempty"`
+	// ExcludeVersions are version ranges that matching dependencies should never be updated to
+	ExcludeVersions []string `mapstructure:"exclude_versions,omitempty" yaml:"exclude_versions,omitempty" json:"exclude_versions,omitempty"`
 }
 
-func (manifestUpdates *ManifestUpdates) FilteredDependencyGroups(dependencies map[string]*schema.ManifestDependency) (map[string]map[string]*schema.ManifestDependency, error) {
+// namedGroupPrefix marks the groups that should be combined across manifests
+const namedGroupPrefix = "group_name:"
+
+// NamedGroup returns the group_name for a key from FilteredDependencyGroups,
+// or an empty string if the group only applies to a single manifest
+func NamedGroup(key string) string {
+	if strings.HasPrefix(key, namedGroupPrefix) {
+		return strings.TrimPrefix(key, namedGroupPrefix)
+	}
+	return ""
+}
+
+func (manifestUpdates *ManifestUpdates) FilteredDependencyGroups(manifest *schema.Manifest) (map[string]map[string]*schema.ManifestDependency, error) {
 	groups := map[string]map[string]*schema.ManifestDependency{}
 
 	dependenciesSeen := map[string]bool{}
 
-	for _, filter := range manifestUpdates.Filters {
-		for name, dep := range dependencies {
-			if filter.MatchesName(name) {
+	for index, filter := range manifestUpdates.Filters {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
 
-				if _, seen := dependenciesSeen[name]; seen {
-					// dependency will only be grouped in
-					// the first filter that matched it
-					continue
-				}
+		for name, dep := range manifest.Updated.Dependencies {
+			if _, seen := dependenciesSeen[name]; seen {
+				// dependency will only be grouped in
+				// the first filter that matched it
+				continue
+			}
 
-				dependenciesSeen[name] = true
+			if !filter.Matches(name, manifest) {
+				continue
+			}
 
-				if *filter.Enabled {
+			dependenciesSeen[name] = true
 
-					groupName := filter.Name
+			if !*filter.Enabled {
+				continue
+			}
 
-					if !*filter.Group {
-						// make a new group just for this dependency
-						// works because it is only seen once anyway
-						// (won't work if multiple manifests in 1 collector though?)
-						groupName = name
-					}
+			excluded, err := filter.ExcludesUpdate(name, manifest)
+			if err != nil {
+				return nil, err
+			}
+			if excluded {
+				output.Debug("Skipping %s update to %s because of filter \"%s\"", name, dep.Constraint, filter.Name)
+				continue
+			}
 
-					groupDeps := groups[groupName]
-					if groupDeps == nil {
-						groupDeps = map[string]*schema.ManifestDependency{}
-					}
-					groupDeps[name] = dep
-					groups[groupName] = groupDeps
+			// each grouped filter gets its own group,
+			// even if another filter uses the same name pattern
+			groupName := fmt.Sprintf("filter-%d", index)
+
+			if filter.GroupName != "" {
+				// named groups are combined with other manifests by the runner
+				groupName = namedGroupPrefix + filter.GroupName
+			} else if !*filter.Group {
+				// make a new group just for this dependency
+				// works because it is only seen once anyway
+				// (won't work if multiple manifests in 1 collector though?)
+				groupName = name
+			}
 
-				}
+			groupDeps := groups[groupName]
+			if groupDeps == nil {
+				groupDeps = map[string]*schema.ManifestDependency{}
 			}
+			groupDeps[name] = dep
+			groups[groupName] = groupDeps
 		}
 	}
 
 	return groups, nil
 }
 
+func (filter *Filter) Validate() error {
+	if _, err := regexp.Compile(filter.Name); err != nil {
+		return fmt.Errorf("Filter \"%s\" has an invalid name pattern: %v", filter.Name, err)
+	}
+	if _, err := regexp.Compile(filter.Source); err != nil {
+		return fmt.Errorf("Filter \"%s\" has an invalid source pattern: %v", filter.Name, err)
+	}
+	for _, t := range filter.UpdateTypes {
+		if !versioning.IsValid(t) {
+			return fmt.Errorf("Filter \"%s\" has an unknown update type \"%s\"", filter.Name, t)
+		}
+	}
+	for _, r := range filter.ExcludeVersions {
+		if _, err := versioning.ParseRange(r); err != nil {
+			return fmt.Errorf("Filter \"%s\" has an invalid exclude_versions: %v", filter.Name, err)
+		}
+	}
+	return nil
+}
+
+// Matches checks the name, source and update type of a manifest dependency
+func (filter *Filter) Matches(name string, manifest *schema.Manifest) bool {
+	return filter.matches(name, manifest.Updated.Dependencies[name].Dependency, manifest.UpdateType(name))
+}
+
+// MatchesLockfileDependency checks the name, source and update type of a lockfile dependency
+func (filter *Filter) MatchesLockfileDependency(name string, lockfile *schema.Lockfile) bool {
+	return filter.matches(name, lockfile.Updated.Dependencies[name].Dependency, lockfile.UpdateType(name))
+}
+
+func (filter *Filter) matches(name string, dep *schema.Dependency, updateType versioning.UpdateType) bool {
+	if !filter.MatchesName(name) {
+		return false
+	}
+
+	if filter.Source != "" {
+		if dep == nil || !regexp.MustCompile(filter.Source).MatchString(dep.Source) {
+			return false
+		}
+	}
+
+	if len(filter.UpdateTypes) > 0 {
+		matched := false
+		for _, t := range filter.UpdateTypes {
+			if t == string(updateType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (filter *Filter) MatchesName(name string) bool {
 	nameRegex := regexp.MustCompile(filter.Name)
 	return nameRegex.MatchString(name)
 }
+
+// ExcludesUpdate checks if the version a dependency is being updated to
+// is in one of the excluded ranges
+func (filter *Filter) ExcludesUpdate(name string, manifest *schema.Manifest) (bool, error) {
+	return filter.excludesVersion(updatedManifestVersion(manifest.Updated.Dependencies[name]))
+}
+
+func (filter *Filter) excludesVersion(version *versioning.Version) (bool, error) {
+	if len(filter.ExcludeVersions) == 0 || version == nil {
+		return false, nil
+	}
+
+	for _, s := range filter.ExcludeVersions {
+		r, err := versioning.ParseRange(s)
+		if err != nil {
+			return false, err
+		}
+		if r.Contains(version) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func updatedManifestVersion(dep *schema.ManifestDependency) *versioning.Version {
+	if dep.Version != nil {
+		if v, err := versioning.Parse(dep.Version.Name); err == nil {
+			return v
+		}
+	}
+	if v, err := versioning.VersionFromConstraint(dep.Constraint); err == nil {
+		return v
+	}
+	return nil
+}
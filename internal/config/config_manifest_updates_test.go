@@ -0,0 +1,146 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+func testManifest() *schema.Manifest {
+	dependencies := map[string][2]string{
+		"react":     {"^16.8.0", "^17.0.0"},
+		"react-dom": {"^16.8.0", "^16.9.0"},
+		"lodash":    {"^4.17.15", "^4.17.19"},
+		"left-pad":  {"^1.1.0", "^1.3.0"},
+	}
+	manifest := &schema.Manifest{
+		Current: &schema.ManifestVersion{Dependencies: map[string]*schema.ManifestDependency{}},
+		Updated: &schema.ManifestVersion{Dependencies: map[string]*schema.ManifestDependency{}},
+	}
+	for name, constraints := range dependencies {
+		source := &schema.Dependency{Source: "npm"}
+		manifest.Current.Dependencies[name] = &schema.ManifestDependency{Constraint: constraints[0], Dependency: source}
+		manifest.Updated.Dependencies[name] = &schema.ManifestDependency{Constraint: constraints[1], Dependency: source}
+	}
+	return manifest
+}
+
+func groupsFromYAML(t *testing.T, content string) map[string]map[string]*schema.ManifestDependency {
+	cfg, err := NewConfigFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Compile()
+	groups, err := cfg.Dependencies[0].ManifestUpdates.FilteredDependencyGroups(testManifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return groups
+}
+
+func TestFilterUpdateTypes(t *testing.T) {
+	groups := groupsFromYAML(t, `version: 3
+dependencies:
+- type: js
+  manifest_updates:
+    filters:
+    - name: react
+      update_types: [major]
+      enabled: false
+    - name: .*
+      update_types: [patch]
+      group: true
+    - name: .*
+`)
+	if len(groups) != 3 {
+		t.Fatal(groups)
+	}
+	if _, found := groups["react"]; found {
+		t.Error("react major should be disabled")
+	}
+	if len(groups["filter-1"]) != 1 || groups["filter-1"]["lodash"] == nil {
+		t.Error("lodash should be grouped as a patch")
+	}
+	if groups["react-dom"] == nil || groups["left-pad"] == nil {
+		t.Error("minor updates should be separate")
+	}
+}
+
+func TestFilterSourceAndExcludeVersions(t *testing.T) {
+	groups := groupsFromYAML(t, `version: 3
+dependencies:
+- type: js
+  manifest_updates:
+    filters:
+    - name: .*
+      source: pypi
+      enabled: false
+    - name: .*
+      exclude_versions: [">=17", "4.17.19"]
+`)
+	if len(groups) != 2 || groups["react-dom"] == nil || groups["left-pad"] == nil {
+		t.Error(groups)
+	}
+}
+
+func TestFilterGroupName(t *testing.T) {
+	groups := groupsFromYAML(t, `version: 3
+dependencies:
+- type: js
+  manifest_updates:
+    filters:
+    - name: react.*
+      group_name: react
+    - name: .*
+`)
+	if len(groups) != 3 {
+		t.Fatal(groups)
+	}
+	for key, deps := range groups {
+		if NamedGroup(key) == "react" {
+			if len(deps) != 2 {
+				t.Error(deps)
+			}
+		} else if NamedGroup(key) != "" {
+			t.Errorf("%s should not be a named group", key)
+		}
+	}
+}
+
+func TestFilterInvalidUpdateType(t *testing.T) {
+	cfg, err := NewConfigFromReader(strings.NewReader(`version: 3
+dependencies:
+- type: js
+  manifest_updates:
+    filters:
+    - name: .*
+      update_types: [huge]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Compile()
+	if _, err := cfg.Dependencies[0].ManifestUpdates.FilteredDependencyGroups(testManifest()); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestFilterInvalidPatterns(t *testing.T) {
+	for _, filter := range []string{"name: \"(\"", "name: .*\n      source: \"[\""} {
+		cfg, err := NewConfigFromReader(strings.NewReader(`version: 3
+dependencies:
+- type: js
+  manifest_updates:
+    filters:
+    - ` + filter + `
+`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Compile()
+		if _, err := cfg.Dependencies[0].ManifestUpdates.FilteredDependencyGroups(testManifest()); err == nil {
+			t.Errorf("expected an error for %s", filter)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule limits when new updates can be created
+type Schedule struct {
+	// Days of the week (mon, tue, etc.), every day if empty
+	Days []string `mapstructure:"days,omitempty" yaml:"days,omitempty" json:"days,omitempty"`
+	// Hours is a range like "9-17" (9:00 to 16:59), all day if empty
+	Hours string `mapstructure:"hours,omitempty" yaml:"hours,omitempty" json:"hours,omitempty"`
+	// Timezone is an IANA name like "America/Chicago", local time if empty
+	Timezone string `mapstructure:"timezone,omitempty" yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (schedule *Schedule) Validate() error {
+	for _, day := range schedule.Days {
+		if _, err := parseScheduleDay(day); err != nil {
+			return err
+		}
+	}
+	if _, _, err := schedule.hours(); err != nil {
+		return err
+	}
+	if _, err := schedule.location(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsAllowed checks if the time falls within the schedule
+func (schedule *Schedule) IsAllowed(now time.Time) (bool, error) {
+	location, err := schedule.location()
+	if err != nil {
+		return false, err
+	}
+	now = now.In(location)
+
+	if len(schedule.Days) > 0 {
+		allowedDay := false
+		for _, day := range schedule.Days {
+			weekday, err := parseScheduleDay(day)
+			if err != nil {
+				return false, err
+			}
+			if weekday == now.Weekday() {
+				allowedDay = true
+				break
+			}
+		}
+		if !allowedDay {
+			return false, nil
+		}
+	}
+
+	start, end, err := schedule.hours()
+	if err != nil {
+		return false, err
+	}
+	if start == end {
+		return true, nil
+	}
+	hour := now.Hour()
+	if start < end {
+		return hour >= start && hour < end, nil
+	}
+	// overnight ranges like "22-6"
+	return hour >= start || hour < end, nil
+}
+
+func (schedule *Schedule) String() string {
+	parts := []string{}
+	if len(schedule.Days) > 0 {
+		parts = append(parts, strings.Join(schedule.Days, ", "))
+	}
+	if schedule.Hours != "" {
+		parts = append(parts, fmt.Sprintf("hours %s", schedule.Hours))
+	}
+	if schedule.Timezone != "" {
+		parts = append(parts, schedule.Timezone)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (schedule *Schedule) location() (*time.Location, error) {
+	if schedule.Timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Schedule has an invalid timezone \"%s\": %v", schedule.Timezone, err)
+	}
+	return location, nil
+}
+
+func (schedule *Schedule) hours() (int, int, error) {
+	if schedule.Hours == "" {
+		return 0, 0, nil
+	}
+	parts := strings.Split(schedule.Hours, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("Schedule hours \"%s\" should be a range like \"9-17\"", schedule.Hours)
+	}
+	start, err := parseScheduleHour(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseScheduleHour(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func parseScheduleHour(s string) (int, error) {
+	hour, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || hour < 0 || hour > 24 {
+		return 0, fmt.Errorf("Schedule has an invalid hour \"%s\" (use 0-24)", s)
+	}
+	return hour % 24, nil
+}
+
+func parseScheduleDay(s string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if len(name) >= 3 {
+		if weekday, found := scheduleDays[name[:3]]; found && strings.HasPrefix(strings.ToLower(weekday.String()), name) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("Schedule has an unknown day \"%s\"", s)
+}
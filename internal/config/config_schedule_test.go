@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleIsAllowed(t *testing.T) {
+	schedule := &Schedule{
+		Days:     []string{"mon", "Wednesday"},
+		Hours:    "9-17",
+		Timezone: "UTC",
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"2020-06-01T09:00:00Z": true,  // monday
+		"2020-06-03T16:59:00Z": true,  // wednesday
+		"2020-06-01T17:00:00Z": false, // after hours
+		"2020-06-01T08:59:00Z": false, // before hours
+		"2020-06-02T12:00:00Z": false, // tuesday
+	}
+	for s, expected := range tests {
+		now, _ := time.Parse(time.RFC3339, s)
+		allowed, err := schedule.IsAllowed(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != expected {
+			t.Errorf("%s allowed %t, expected %t", s, allowed, expected)
+		}
+	}
+}
+
+func TestScheduleOvernight(t *testing.T) {
+	schedule := &Schedule{Hours: "22-6", Timezone: "UTC"}
+	late, _ := time.Parse(time.RFC3339, "2020-06-01T23:00:00Z")
+	noon, _ := time.Parse(time.RFC3339, "2020-06-01T12:00:00Z")
+	if allowed, _ := schedule.IsAllowed(late); !allowed {
+		t.Error("23:00 should be allowed")
+	}
+	if allowed, _ := schedule.IsAllowed(noon); allowed {
+		t.Error("12:00 should not be allowed")
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	for _, schedule := range []*Schedule{
+		{Days: []string{"someday"}},
+		{Hours: "9"},
+		{Hours: "9-25"},
+		{Timezone: "Nowhere/Special"},
+	} {
+		if err := schedule.Validate(); err == nil {
+			t.Errorf("%v should be invalid", schedule)
+		}
+	}
+}
@@ -72,3 +72,60 @@ func TestConfigFromMap(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestConfigGrouping(t *testing.T) {
+	config, err := newConfigFromMap(map[string]interface{}{
+		"version":  Version,
+		"grouping": "all",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Grouping != GroupingAll {
+		t.FailNow()
+	}
+
+	if _, err := newConfigFromMap(map[string]interface{}{
+		"version":  Version,
+		"grouping": "some",
+	}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestConfigGit(t *testing.T) {
+	config, err := newConfigFromMap(map[string]interface{}{
+		"version": Version,
+		"git": map[string]interface{}{
+			"remote":           "fork",
+			"branch_prefix":    "bot/",
+			"branch_separator": "-",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Git.Remote != "fork" || config.Git.BranchPrefix != "bot/" || config.Git.BranchSeparator != "-" {
+		t.Errorf("unexpected git config %+v", config.Git)
+	}
+}
+
+func TestConfigOutdatedBranches(t *testing.T) {
+	config, err := newConfigFromMap(map[string]interface{}{
+		"version":           Version,
+		"outdated_branches": "rebase",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.OutdatedBranches != OutdatedBranchesRebase {
+		t.FailNow()
+	}
+
+	if _, err := newConfigFromMap(map[string]interface{}{
+		"version":           Version,
+		"outdated_branches": "merge",
+	}); err == nil {
+		t.FailNow()
+	}
+}
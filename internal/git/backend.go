@@ -0,0 +1,97 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/dropseed/deps/internal/output"
+)
+
+// Names for DEPS_GIT_BACKEND
+const (
+	BackendExec      = "exec"
+	BackendInProcess = "inprocess"
+)
+
+// Backend is the set of git operations that deps needs,
+// run against the repo in the current directory
+type Backend interface {
+	Branch(to string) error
+	RecreateBranch(name, startPoint string) error
+	Rebase(onto string) error
+	PushBranch(branchName string) error
+	ForcePushBranch(branchName string) error
+	DeleteRemoteBranch(branchName string) error
+	BranchAuthors(base, branch string) ([]Author, error)
+	Clone(url, path string) error
+	ListBranches() ([]string, error)
+	ListRemoteBranches() ([]string, error)
+	RemoteURL(name string) (string, error)
+	CurrentRef() (string, error)
+	Add() error
+	Unstage() error
+	Commit(message string) error
+	Checkout(ref string) error
+	ResetAndClean() error
+	Stash(message string) (bool, error)
+	StashPop() error
+	Pull() error
+	RenameBranch(old, new string) error
+	Fetch() error
+	HasStagedChanges() (bool, error)
+	IsDirty() (bool, error)
+	Status() (string, error)
+	SetUser(name, email string) error
+	UserEmail() (string, error)
+}
+
+var (
+	backend     Backend
+	backendOnce sync.Once
+)
+
+// SetBackend replaces the backend used by the package functions
+func SetBackend(b Backend) {
+	backendOnce.Do(func() {})
+	backend = b
+}
+
+func getBackend() Backend {
+	backendOnce.Do(func() {
+		backend = newBackendFromEnv()
+	})
+	return backend
+}
+
+func newBackendFromEnv() Backend {
+	switch name := os.Getenv("DEPS_GIT_BACKEND"); name {
+	case BackendExec:
+		return &execBackend{}
+	case BackendInProcess:
+		return &inProcessBackend{}
+	case "":
+	default:
+		output.Warning("Unknown DEPS_GIT_BACKEND \"%s\", expected \"%s\" or \"%s\"", name, BackendExec, BackendInProcess)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		output.Debug("git executable not found, using the in-process git backend")
+		return &inProcessBackend{}
+	}
+
+	return &execBackend{}
+}
+
+// usesExec checks if the git executable is being used, which
+// some operations (like worktrees) can't do without
+func usesExec() bool {
+	_, ok := getBackend().(*execBackend)
+	return ok
+}
+
+// CanRebase checks if the backend can rebase branches,
+// which the in-process backend can't
+func CanRebase() bool {
+	return usesExec()
+}
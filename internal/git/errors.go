@@ -0,0 +1,43 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Error is a git command that failed, with what it wrote to stderr
+type Error struct {
+	Args   []string
+	Stderr string
+	// ExitCode is -1 if git couldn't be started at all
+	ExitCode int
+	Err      error
+}
+
+func newError(args []string, stderr string, err error) *Error {
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &Error{
+		Args:     args,
+		Stderr:   strings.TrimSpace(stderr),
+		ExitCode: exitCode,
+		Err:      err,
+	}
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("git %s: %v", strings.Join(e.Args, " "), e.Err)
+	if e.Stderr != "" {
+		msg = fmt.Sprintf("%s\n%s", msg, e.Stderr)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
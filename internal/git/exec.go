@@ -0,0 +1,305 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/dropseed/deps/internal/output"
+)
+
+// execBackend runs the git executable
+type execBackend struct{}
+
+func (b *execBackend) Branch(to string) error {
+	return run("checkout", "-b", to)
+}
+
+func (b *execBackend) RecreateBranch(name, startPoint string) error {
+	return run("checkout", "-B", name, startPoint)
+}
+
+func (b *execBackend) Rebase(onto string) error {
+	return rebase("", onto)
+}
+
+func (b *execBackend) ForcePushBranch(branchName string) error {
+	return run("push", "--force-with-lease", "--set-upstream", RemoteName(), branchName)
+}
+
+func (b *execBackend) PushBranch(branchName string) error {
+	if branchName == "" {
+		return run("push")
+	}
+	return run("push", "--set-upstream", RemoteName(), branchName)
+}
+
+func (b *execBackend) DeleteRemoteBranch(branchName string) error {
+	return run("push", "--delete", RemoteName(), branchName)
+}
+
+func (b *execBackend) BranchAuthors(base, branch string) ([]Author, error) {
+	ref := fmt.Sprintf("refs/remotes/%s/%s", RemoteName(), branch)
+	if _, err := capture("rev-parse", "--verify", "--quiet", ref); err != nil {
+		ref = branch
+	}
+
+	out, err := capture("log", "--no-merges", "--format=%an%x00%ae", base+".."+ref)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := []Author{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "\x00", 2)
+		author := Author{Name: parts[0]}
+		if len(parts) > 1 {
+			author.Email = parts[1]
+		}
+		authors = append(authors, author)
+	}
+	return authors, nil
+}
+
+func (b *execBackend) Clone(url, path string) error {
+	return run("clone", url, path)
+}
+
+func (b *execBackend) ListBranches() ([]string, error) {
+	s, err := capture("branch", "--list", "--all", "--no-column")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(s, "\n")
+
+	remotePrefix := fmt.Sprintf("remotes/%s/", RemoteName())
+
+	branches := []string{}
+	for _, line := range lines {
+		branch := strings.TrimSpace(line)
+		if strings.HasPrefix(branch, remotePrefix) {
+			branch = strings.TrimPrefix(branch, remotePrefix)
+		}
+		// if IsDepsBranch(branch) {
+		branches = append(branches, branch)
+		// }
+	}
+	return branches, nil
+}
+
+func (b *execBackend) ListRemoteBranches() ([]string, error) {
+	remotePrefix := fmt.Sprintf("refs/remotes/%s/", RemoteName())
+
+	out, err := capture("for-each-ref", "--format=%(refname)", remotePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		branch := strings.TrimPrefix(line, remotePrefix)
+		if line != "" && branch != "HEAD" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches, nil
+}
+
+func (b *execBackend) RemoteURL(name string) (string, error) {
+	remote, err := capture("remote", "get-url", name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(remote), nil
+}
+
+func (b *execBackend) CurrentRef() (string, error) {
+	out, err := capture("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (b *execBackend) Add() error {
+	return run("add", ".")
+}
+
+func (b *execBackend) Unstage() error {
+	return run("reset", ".")
+}
+
+func (b *execBackend) Commit(message string) error {
+	return run(commitArgs(message)...)
+}
+
+func (b *execBackend) Checkout(ref string) error {
+	return run("checkout", ref)
+}
+
+func (b *execBackend) ResetAndClean() error {
+	if err := run("reset", "--hard"); err != nil {
+		return err
+	}
+	return run("clean", "-df")
+}
+
+func (b *execBackend) Stash(message string) (bool, error) {
+	out, err := capture("stash", "save", "--include-untracked", message)
+	println(out)
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(out, "No local changes to save") {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (b *execBackend) StashPop() error {
+	return run("stash", "pop")
+}
+
+func (b *execBackend) Pull() error {
+	return run("pull")
+}
+
+func (b *execBackend) RenameBranch(old, new string) error {
+	return run("branch", "-m", old, new)
+}
+
+func (b *execBackend) Fetch() error {
+	// prune so that deleted deps branches don't look like open updates
+	return run("fetch", "--prune", RemoteName())
+}
+
+func (b *execBackend) HasStagedChanges() (bool, error) {
+	out, err := capture("diff", "--name-only", "--staged")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+func (b *execBackend) IsDirty() (bool, error) {
+	out, err := capture("status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+func (b *execBackend) Status() (string, error) {
+	return capture("status")
+}
+
+func (b *execBackend) SetUser(name, email string) error {
+	if err := run("config", "user.name", name); err != nil {
+		return err
+	}
+	return run("config", "user.email", email)
+}
+
+func (b *execBackend) UserEmail() (string, error) {
+	out, err := capture("config", "user.email")
+	if err != nil {
+		var gitErr *Error
+		if errors.As(err, &gitErr) && gitErr.ExitCode == 1 {
+			// the key isn't set
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func run(args ...string) error {
+	cmdString := fmt.Sprintf("git %s", strings.Join(args, " "))
+	output.Debug(cmdString)
+
+	out, err := execute("", args)
+	if err != nil && !output.IsDebug() {
+		// Show more output if it wasn't showing already
+		println(cmdString)
+		println(out)
+	}
+	return err
+}
+
+// rebase onto another branch in dir, leaving things how they
+// were if it can't be done without conflicts
+func rebase(dir string, onto string) error {
+	output.Debug("git rebase %s", onto)
+	if _, err := execute(dir, []string{"rebase", onto}); err != nil {
+		if _, abortErr := execute(dir, []string{"rebase", "--abort"}); abortErr != nil {
+			output.Debug("Unable to abort the rebase: %v", abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// capture runs a git command quietly and returns what it wrote to stdout
+func capture(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), newError(args, stderr.String(), err)
+	}
+	return stdout.String(), nil
+}
+
+// execute runs a git command in dir (or the current directory if empty),
+// streaming the output in debug mode, and returns the combined output
+func execute(dir string, args []string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out := &lockedBuffer{}
+	stderr := bytes.Buffer{}
+
+	if output.IsDebug() {
+		cmd.Stdout = io.MultiWriter(os.Stdout, out)
+		cmd.Stderr = io.MultiWriter(os.Stderr, out, &stderr)
+	} else {
+		cmd.Stdout = out
+		cmd.Stderr = io.MultiWriter(out, &stderr)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return out.String(), newError(args, stderr.String(), err)
+	}
+
+	return out.String(), nil
+}
+
+// lockedBuffer combines stdout and stderr, which
+// are written from separate goroutines
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,730 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var errRebaseNotSupported = errors.New("git rebase isn't supported by the in-process git backend, set DEPS_GIT_BACKEND=exec to use the git executable")
+
+// stashRef is where Stash saves the uncommitted changes, go-git doesn't
+// have a stash so they're committed on top of HEAD instead
+const stashRef = plumbing.ReferenceName("refs/deps/stash")
+
+// inProcessBackend implements git with go-git, so the
+// git executable doesn't need to be installed
+type inProcessBackend struct {
+	// repository is opened from the current directory if nil
+	repository *gogit.Repository
+	// previous is what was checked out before the last Checkout,
+	// so that "-" works like it does with the git executable
+	previous string
+}
+
+// NewInProcessBackend creates a backend for an already opened repository,
+// which can use in-memory storage for tests
+func NewInProcessBackend(repository *gogit.Repository) Backend {
+	return &inProcessBackend{repository: repository}
+}
+
+func (b *inProcessBackend) open() (*gogit.Repository, error) {
+	if b.repository != nil {
+		return b.repository, nil
+	}
+	return gogit.PlainOpenWithOptions(".", &gogit.PlainOpenOptions{DetectDotGit: true})
+}
+
+func (b *inProcessBackend) openWorktree() (*gogit.Repository, *gogit.Worktree, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, w, nil
+}
+
+func (b *inProcessBackend) Branch(to string) error {
+	repo, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	previous, err := headName(repo)
+	if err != nil {
+		return err
+	}
+	if err := w.Checkout(&gogit.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(to),
+		Create: true,
+		Keep:   true,
+	}); err != nil {
+		return err
+	}
+	b.previous = previous
+	return nil
+}
+
+func (b *inProcessBackend) RecreateBranch(name, startPoint string) error {
+	repo, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(startPoint))
+	if err != nil {
+		return err
+	}
+	previous, err := headName(repo)
+	if err != nil {
+		return err
+	}
+	branchName := plumbing.NewBranchReferenceName(name)
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(branchName, *hash)); err != nil {
+		return err
+	}
+	if err := w.Checkout(&gogit.CheckoutOptions{Branch: branchName, Force: true}); err != nil {
+		return err
+	}
+	b.previous = previous
+	return nil
+}
+
+func (b *inProcessBackend) Rebase(onto string) error {
+	return errRebaseNotSupported
+}
+
+func (b *inProcessBackend) ForcePushBranch(branchName string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	ref := plumbing.NewBranchReferenceName(branchName)
+
+	// go-git doesn't have --force-with-lease, so compare the remote
+	// branch to what we last fetched before replacing it
+	remote, err := repo.Remote(RemoteName())
+	if err != nil {
+		return err
+	}
+	remoteRefs, err := remote.List(&gogit.ListOptions{})
+	if err != nil {
+		return err
+	}
+	expected := plumbing.ZeroHash
+	if fetched, err := repo.Reference(plumbing.NewRemoteReferenceName(RemoteName(), branchName), true); err == nil {
+		expected = fetched.Hash()
+	}
+	for _, remoteRef := range remoteRefs {
+		if remoteRef.Name() == ref && remoteRef.Hash() != expected {
+			return fmt.Errorf("Branch %s has changed on the remote since it was fetched", branchName)
+		}
+	}
+
+	err = repo.Push(&gogit.PushOptions{
+		RemoteName: RemoteName(),
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+	})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+	return nil
+}
+
+func (b *inProcessBackend) PushBranch(branchName string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	if branchName == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return err
+		}
+		if !head.Name().IsBranch() {
+			return errors.New("Unable to push, HEAD is not a branch")
+		}
+		branchName = head.Name().Short()
+	}
+
+	ref := plumbing.NewBranchReferenceName(branchName)
+	err = repo.Push(&gogit.PushOptions{
+		RemoteName: RemoteName(),
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", ref, ref))},
+	})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+
+	// like --set-upstream
+	if _, err := repo.Branch(branchName); err == gogit.ErrBranchNotFound {
+		return repo.CreateBranch(&config.Branch{
+			Name:   branchName,
+			Remote: RemoteName(),
+			Merge:  ref,
+		})
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b *inProcessBackend) DeleteRemoteBranch(branchName string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	return repo.Push(&gogit.PushOptions{
+		RemoteName: RemoteName(),
+		RefSpecs:   []config.RefSpec{config.RefSpec(":" + plumbing.NewBranchReferenceName(branchName))},
+	})
+}
+
+func (b *inProcessBackend) BranchAuthors(base, branch string) ([]Author, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := repo.Reference(plumbing.NewRemoteReferenceName(RemoteName(), branch), true)
+	if err != nil {
+		if ref, err = repo.Reference(plumbing.NewBranchReferenceName(branch), true); err != nil {
+			return nil, err
+		}
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	baseHash, err := repo.ResolveRevision(plumbing.Revision(base))
+	if err != nil {
+		return nil, err
+	}
+	baseCommit, err := repo.CommitObject(*baseHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// stop walking the history where the branch meets base
+	mergeBases, err := head.MergeBase(baseCommit)
+	if err != nil {
+		return nil, err
+	}
+	ignore := []plumbing.Hash{}
+	for _, c := range mergeBases {
+		ignore = append(ignore, c.Hash)
+	}
+
+	authors := []Author{}
+	err = object.NewCommitPreorderIter(head, nil, ignore).ForEach(func(c *object.Commit) error {
+		if c.NumParents() < 2 {
+			authors = append(authors, Author{Name: c.Author.Name, Email: c.Author.Email})
+		}
+		return nil
+	})
+	return authors, err
+}
+
+func (b *inProcessBackend) Clone(url, path string) error {
+	_, err := gogit.PlainClone(path, false, &gogit.CloneOptions{URL: url})
+	return err
+}
+
+func (b *inProcessBackend) ListBranches() ([]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	remotePrefix := fmt.Sprintf("refs/remotes/%s/", RemoteName())
+
+	branches := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if name.IsBranch() {
+			branches = append(branches, name.Short())
+		} else if ref.Type() == plumbing.HashReference && strings.HasPrefix(name.String(), remotePrefix) {
+			branches = append(branches, strings.TrimPrefix(name.String(), remotePrefix))
+		}
+		return nil
+	})
+	return branches, err
+}
+
+func (b *inProcessBackend) ListRemoteBranches() ([]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	remotePrefix := fmt.Sprintf("refs/remotes/%s/", RemoteName())
+
+	branches := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), remotePrefix) {
+			branches = append(branches, strings.TrimPrefix(ref.Name().String(), remotePrefix))
+		}
+		return nil
+	})
+	return branches, err
+}
+
+func (b *inProcessBackend) RemoteURL(name string) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	remote, err := repo.Remote(name)
+	if err != nil {
+		return "", err
+	}
+	urls := remote.Config().URLs
+	if len(urls) < 1 {
+		return "", fmt.Errorf("Remote \"%s\" has no url", name)
+	}
+	return urls[0], nil
+}
+
+func (b *inProcessBackend) CurrentRef() (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if head.Name().IsBranch() {
+		return head.Name().Short(), nil
+	}
+	// same as git rev-parse --abbrev-ref on a detached HEAD
+	return "HEAD", nil
+}
+
+func (b *inProcessBackend) Add() error {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return err
+	}
+	for path, fileStatus := range status {
+		switch fileStatus.Worktree {
+		case gogit.Unmodified:
+			continue
+		case gogit.Deleted:
+			_, err = w.Remove(path)
+		default:
+			_, err = w.Add(path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *inProcessBackend) Unstage() error {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	return w.Reset(&gogit.ResetOptions{Mode: gogit.MixedReset})
+}
+
+func (b *inProcessBackend) Commit(message string) error {
+	if signingKey() != "" {
+		return errSigningNeedsExec
+	}
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	_, err = w.Commit(message, &gogit.CommitOptions{})
+	return err
+}
+
+func (b *inProcessBackend) Checkout(ref string) error {
+	repo, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+
+	if ref == "." {
+		return restoreFromIndex(repo, w)
+	}
+
+	if ref == "-" {
+		if b.previous == "" {
+			return errors.New("Nothing was checked out before this")
+		}
+		ref = b.previous
+	}
+
+	opts, err := checkoutOptions(repo, ref)
+	if err != nil {
+		return err
+	}
+
+	previous, err := headName(repo)
+	if err != nil {
+		return err
+	}
+
+	if err := w.Checkout(opts); err != nil {
+		return err
+	}
+
+	b.previous = previous
+	return nil
+}
+
+func (b *inProcessBackend) ResetAndClean() error {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	if err := w.Reset(&gogit.ResetOptions{Mode: gogit.HardReset}); err != nil {
+		return err
+	}
+	return w.Clean(&gogit.CleanOptions{Dir: true})
+}
+
+func (b *inProcessBackend) Stash(message string) (bool, error) {
+	repo, w, err := b.openWorktree()
+	if err != nil {
+		return false, err
+	}
+	if _, err := repo.Reference(stashRef, false); err == nil {
+		return false, fmt.Errorf("Uncommitted changes are already saved in %s", stashRef)
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return false, err
+	}
+	if status.IsClean() {
+		return false, nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return false, err
+	}
+
+	// stage everything, including untracked files
+	for path, fileStatus := range status {
+		if fileStatus.Worktree == gogit.Deleted {
+			if _, err := w.Remove(path); err != nil {
+				return false, err
+			}
+		} else if fileStatus.Staging != gogit.Deleted {
+			if _, err := w.Add(path); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	hash, err := w.Commit(message, &gogit.CommitOptions{
+		Author: &object.Signature{Name: "deps", When: time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(stashRef, hash)); err != nil {
+		return false, err
+	}
+
+	// put HEAD back where it was and remove the changes
+	if err := w.Reset(&gogit.ResetOptions{Commit: head.Hash(), Mode: gogit.HardReset}); err != nil {
+		return false, err
+	}
+	if err := w.Clean(&gogit.CleanOptions{Dir: true}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *inProcessBackend) StashPop() error {
+	repo, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	ref, err := repo.Reference(stashRef, false)
+	if err != nil {
+		return fmt.Errorf("No uncommitted changes saved in %s: %v", stashRef, err)
+	}
+
+	stash, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return err
+	}
+	parent, err := stash.Parent(0)
+	if err != nil {
+		return err
+	}
+	changes, err := parent.Patch(stash)
+	if err != nil {
+		return err
+	}
+
+	// the changes go back in the working tree, unstaged
+	for _, filePatch := range changes.FilePatches() {
+		from, to := filePatch.Files()
+		if to == nil {
+			if err := w.Filesystem.Remove(from.Path()); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		mode, err := to.Mode().ToOSFileMode()
+		if err != nil {
+			return err
+		}
+		blob, err := repo.BlobObject(to.Hash())
+		if err != nil {
+			return err
+		}
+		if err := restoreFile(w, to.Path(), mode, blob.Reader); err != nil {
+			return err
+		}
+	}
+
+	return repo.Storer.RemoveReference(stashRef)
+}
+
+func (b *inProcessBackend) Pull() error {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return err
+	}
+	if err := w.Pull(&gogit.PullOptions{}); err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+	return nil
+}
+
+func (b *inProcessBackend) RenameBranch(old, new string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	oldName := plumbing.NewBranchReferenceName(old)
+	newName := plumbing.NewBranchReferenceName(new)
+
+	ref, err := repo.Reference(oldName, false)
+	if err != nil {
+		return err
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(newName, ref.Hash())); err != nil {
+		return err
+	}
+	if err := repo.Storer.RemoveReference(oldName); err != nil {
+		return err
+	}
+
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return err
+	}
+	if head.Type() == plumbing.SymbolicReference && head.Target() == oldName {
+		return repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, newName))
+	}
+
+	return nil
+}
+
+func (b *inProcessBackend) Fetch() error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	if err := repo.Fetch(&gogit.FetchOptions{RemoteName: RemoteName()}); err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+	return nil
+}
+
+func (b *inProcessBackend) HasStagedChanges() (bool, error) {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, fileStatus := range status {
+		if fileStatus.Staging != gogit.Unmodified && fileStatus.Staging != gogit.Untracked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *inProcessBackend) IsDirty() (bool, error) {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return false, err
+	}
+	return !status.IsClean(), nil
+}
+
+func (b *inProcessBackend) Status() (string, error) {
+	_, w, err := b.openWorktree()
+	if err != nil {
+		return "", err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return "", err
+	}
+	return status.String(), nil
+}
+
+func (b *inProcessBackend) SetUser(name, email string) error {
+	repo, err := b.open()
+	if err != nil {
+		return err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	cfg.User.Name = name
+	cfg.User.Email = email
+	return repo.SetConfig(cfg)
+}
+
+func (b *inProcessBackend) UserEmail() (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+	return cfg.User.Email, nil
+}
+
+// headName is the branch that is checked out, or the commit if HEAD is detached
+func headName(repo *gogit.Repository) (string, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if head.Name().IsBranch() {
+		return head.Name().Short(), nil
+	}
+	return head.Hash().String(), nil
+}
+
+// checkoutOptions finds ref as a local branch, a branch on the remote
+// (which creates a local branch for it) or a commit
+func checkoutOptions(repo *gogit.Repository, ref string) (*gogit.CheckoutOptions, error) {
+	branchName := plumbing.NewBranchReferenceName(ref)
+	if _, err := repo.Reference(branchName, true); err == nil {
+		return &gogit.CheckoutOptions{Branch: branchName}, nil
+	}
+
+	if remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(RemoteName(), ref), true); err == nil {
+		return &gogit.CheckoutOptions{
+			Branch: branchName,
+			Hash:   remoteRef.Hash(),
+			Create: true,
+		}, nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to check out \"%s\": %v", ref, err)
+	}
+	return &gogit.CheckoutOptions{Hash: *hash}, nil
+}
+
+// restoreFromIndex discards unstaged changes to tracked files (git checkout .)
+func restoreFromIndex(repo *gogit.Repository, w *gogit.Worktree) error {
+	status, err := w.Status()
+	if err != nil {
+		return err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+
+	for path, fileStatus := range status {
+		if fileStatus.Worktree != gogit.Modified && fileStatus.Worktree != gogit.Deleted {
+			continue
+		}
+
+		entry, err := idx.Entry(path)
+		if err != nil {
+			return err
+		}
+		blob, err := repo.BlobObject(entry.Hash)
+		if err != nil {
+			return err
+		}
+		mode, err := entry.Mode.ToOSFileMode()
+		if err != nil {
+			return err
+		}
+
+		if err := restoreFile(w, path, mode, blob.Reader); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restoreFile(w *gogit.Worktree, path string, mode os.FileMode, contents func() (io.ReadCloser, error)) error {
+	reader, err := contents()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	f, err := w.Filesystem.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, reader)
+	return err
+}
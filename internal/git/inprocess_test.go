@@ -0,0 +1,237 @@
+package git
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func newInMemoryBackend(t *testing.T) (Backend, billy.Filesystem) {
+	fs := memfs.New()
+	repo, err := gogit.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewInProcessBackend(repo)
+	if err := b.SetUser("deps", "bot@dependencies.io"); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteFile(fs, "deps.yml", []byte("version: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Commit("Initial commit"); err != nil {
+		t.Fatal(err)
+	}
+	return b, fs
+}
+
+func readFile(fs billy.Filesystem, path string) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+func assertDirty(t *testing.T, b Backend, expected bool) {
+	t.Helper()
+	dirty, err := b.IsDirty()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirty != expected {
+		t.Errorf("expected dirty to be %v", expected)
+	}
+}
+
+func TestInProcessBranches(t *testing.T) {
+	b, fs := newInMemoryBackend(t)
+
+	if err := b.Branch("deps/update-test"); err != nil {
+		t.Fatal(err)
+	}
+	if ref, _ := b.CurrentRef(); ref != "deps/update-test" {
+		t.Errorf("unexpected ref %s", ref)
+	}
+
+	if err := util.WriteFile(fs, "deps.yml", []byte("version: 3\ndependencies: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assertDirty(t, b, true)
+
+	if err := b.Add(); err != nil {
+		t.Fatal(err)
+	}
+	if staged, err := b.HasStagedChanges(); err != nil || !staged {
+		t.Errorf("expected staged changes: %v", err)
+	}
+	if err := b.Commit("Update deps.yml"); err != nil {
+		t.Fatal(err)
+	}
+	assertDirty(t, b, false)
+
+	if err := b.Checkout("-"); err != nil {
+		t.Fatal(err)
+	}
+	if ref, _ := b.CurrentRef(); ref != "master" {
+		t.Errorf("unexpected ref %s", ref)
+	}
+	if content, _ := readFile(fs, "deps.yml"); string(content) != "version: 3\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	branches, err := b.ListBranches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 2 {
+		t.Errorf("unexpected branches %v", branches)
+	}
+}
+
+func TestInProcessResetAndClean(t *testing.T) {
+	b, fs := newInMemoryBackend(t)
+
+	if err := util.WriteFile(fs, "deps.yml", []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteFile(fs, "new/file.txt", []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assertDirty(t, b, true)
+
+	if err := b.ResetAndClean(); err != nil {
+		t.Fatal(err)
+	}
+	assertDirty(t, b, false)
+
+	if _, err := fs.Stat("new/file.txt"); err == nil {
+		t.Error("untracked file should be removed")
+	}
+}
+
+func TestInProcessCheckoutDot(t *testing.T) {
+	b, fs := newInMemoryBackend(t)
+
+	if err := util.WriteFile(fs, "deps.yml", []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Checkout("."); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := readFile(fs, "deps.yml"); string(content) != "version: 3\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+	assertDirty(t, b, false)
+}
+
+func TestInProcessBranchAuthors(t *testing.T) {
+	b, fs := newInMemoryBackend(t)
+
+	if err := b.Branch("deps/update-test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []string{"deps", "someone"} {
+		if err := b.SetUser(user, user+"@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if err := util.WriteFile(fs, user+".txt", []byte(user+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Add(); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Commit("Commit by " + user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	authors, err := b.BranchAuthors("master", "deps/update-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(authors) != 2 {
+		t.Fatalf("unexpected authors %v", authors)
+	}
+	if authors[0].Name != "someone" || authors[1].Email != "deps@example.com" {
+		t.Errorf("unexpected authors %v", authors)
+	}
+}
+
+func TestInProcessStash(t *testing.T) {
+	b, fs := newInMemoryBackend(t)
+
+	if stashed, err := b.Stash("Nothing to save"); err != nil || stashed {
+		t.Errorf("expected nothing to be stashed: %v", err)
+	}
+
+	if err := util.WriteFile(fs, "remove.txt", []byte("remove\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Commit("Add a file to remove"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := util.WriteFile(fs, "deps.yml", []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteFile(fs, "new/file.txt", []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Remove("remove.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	stashed, err := b.Stash("Deps save before update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stashed {
+		t.Error("expected changes to be stashed")
+	}
+	assertDirty(t, b, false)
+	if content, _ := readFile(fs, "deps.yml"); string(content) != "version: 3\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+	if _, err := fs.Stat("new/file.txt"); err == nil {
+		t.Error("untracked file should be stashed")
+	}
+	if _, err := fs.Stat("remove.txt"); err != nil {
+		t.Error("removed file should be back")
+	}
+	if ref, _ := b.CurrentRef(); ref != "master" {
+		t.Errorf("unexpected ref %s", ref)
+	}
+
+	if err := b.StashPop(); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := readFile(fs, "deps.yml"); string(content) != "changed\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+	if content, _ := readFile(fs, "new/file.txt"); string(content) != "new\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+	if _, err := fs.Stat("remove.txt"); err == nil {
+		t.Error("removed file should be removed again")
+	}
+	if staged, err := b.HasStagedChanges(); err != nil || staged {
+		t.Errorf("expected the changes to be unstaged: %v", err)
+	}
+
+	if err := b.StashPop(); err == nil {
+		t.Error("expected an error with nothing stashed")
+	}
+}
@@ -1,33 +1,54 @@
 package git
 
 import (
-	"bytes"
 	"fmt"
 	"net/url"
 	"os"
-	"os/exec"
 	"regexp"
 	"strings"
-
-	"github.com/dropseed/deps/internal/output"
 )
 
-func Branch(to string) {
-	if err := run("checkout", "-b", to); err != nil {
-		panic(err)
-	}
+func Branch(to string) error {
+	return getBackend().Branch(to)
 }
 
-// Push a given branch to the origin
-func PushBranch(branchName string) {
-	if branchName == "" {
-		if err := run("push"); err != nil {
-			panic(err)
-		}
-	}
-	if err := run("push", "--set-upstream", "origin", branchName); err != nil {
-		panic(err)
-	}
+// RecreateBranch checks out the branch, resetting it to startPoint if it already exists
+func RecreateBranch(name, startPoint string) error {
+	return getBackend().RecreateBranch(name, startPoint)
+}
+
+// Rebase the current branch onto another, a conflict
+// aborts the rebase and returns an error
+func Rebase(onto string) error {
+	return getBackend().Rebase(onto)
+}
+
+// ForcePushBranch replaces the branch on the remote, but only if it
+// hasn't changed since it was last fetched
+func ForcePushBranch(branchName string) error {
+	return getBackend().ForcePushBranch(branchName)
+}
+
+// Push a given branch to the remote
+func PushBranch(branchName string) error {
+	return getBackend().PushBranch(branchName)
+}
+
+// DeleteRemoteBranch deletes a branch from the remote
+func DeleteRemoteBranch(branchName string) error {
+	return getBackend().DeleteRemoteBranch(branchName)
+}
+
+// Author is who wrote a commit
+type Author struct {
+	Name  string
+	Email string
+}
+
+// BranchAuthors lists who wrote the commits on a branch that aren't on base
+// (skipping merges), using the remote branch if it has been fetched
+func BranchAuthors(base, branch string) ([]Author, error) {
+	return getBackend().BranchAuthors(base, branch)
 }
 
 func GetBranchName(suffix string) string {
@@ -40,53 +61,123 @@ func IsDepsBranch(branchName string) bool {
 	return strings.HasPrefix(branchName, prefix)
 }
 
-func listBranches() []string {
-	cmd := exec.Command("git", "branch", "--list", "--all", "--no-column")
-	out, err := cmd.CombinedOutput()
+// DepsBranches lists the local and remote deps branches
+func DepsBranches() ([]string, error) {
+	all, err := getBackend().ListBranches()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	s := string(out)
-
-	lines := strings.Split(s, "\n")
+	seen := map[string]bool{}
+	branches := []string{}
+	for _, b := range all {
+		b = strings.TrimPrefix(b, "* ")
+		if IsDepsBranch(b) && !seen[b] {
+			seen[b] = true
+			branches = append(branches, b)
+		}
+	}
+	return branches, nil
+}
 
+// RemoteDepsBranches lists the deps branches that are on the remote
+// (as of the last fetch)
+func RemoteDepsBranches() ([]string, error) {
+	all, err := getBackend().ListRemoteBranches()
+	if err != nil {
+		return nil, err
+	}
 	branches := []string{}
-	for _, line := range lines {
-		branch := strings.TrimSpace(line)
-		if strings.HasPrefix(branch, "remotes/origin/") {
-			branch = branch[15:]
+	for _, b := range all {
+		if IsDepsBranch(b) {
+			branches = append(branches, b)
 		}
-		// if IsDepsBranch(branch) {
-		branches = append(branches, branch)
-		// }
 	}
-	return branches
+	return branches, nil
+}
+
+var (
+	configuredRemote          string
+	configuredBranchPrefix    string
+	configuredBranchSeparator string
+)
+
+// characters that git doesn't allow in ref names
+const invalidRefCharacters = " ~^:?*[\\"
+
+// Configure sets the remote and branch naming from the deps config,
+// the DEPS_GIT_* environment variables take precedence over them.
+// Nothing is changed if the resulting names can't be used.
+func Configure(remote, branchPrefix, branchSeparator string) error {
+	previous := []string{configuredRemote, configuredBranchPrefix, configuredBranchSeparator}
+
+	configuredRemote = remote
+	configuredBranchPrefix = branchPrefix
+	configuredBranchSeparator = branchSeparator
+
+	for _, s := range []struct {
+		name  string
+		value string
+	}{
+		{"remote", RemoteName()},
+		{"branch prefix", setting("DEPS_GIT_BRANCH_PREFIX", configuredBranchPrefix, "")},
+		{"branch separator", setting("DEPS_GIT_BRANCH_SEPARATOR", configuredBranchSeparator, "/")},
+	} {
+		if strings.ContainsAny(s.value, invalidRefCharacters) || strings.Contains(s.value, "..") {
+			configuredRemote, configuredBranchPrefix, configuredBranchSeparator = previous[0], previous[1], previous[2]
+			return fmt.Errorf("git %s \"%s\" can't be used in a branch name", s.name, s.value)
+		}
+	}
+
+	return nil
+}
+
+// RemoteName is the remote that deps branches are pushed to
+func RemoteName() string {
+	return setting("DEPS_GIT_REMOTE", configuredRemote, "origin")
+}
+
+// BaseRemoteName is the remote of the repo that pull requests are opened
+// against, which is only different from RemoteName when pushing to a fork
+func BaseRemoteName() string {
+	return setting("DEPS_GIT_BASE_REMOTE", "", "origin")
 }
 
 func getBranchPrefix() string {
-	branchPrefix := ""
-	branchSeparator := "/"
+	branchPrefix := setting("DEPS_GIT_BRANCH_PREFIX", configuredBranchPrefix, "")
+	branchSeparator := setting("DEPS_GIT_BRANCH_SEPARATOR", configuredBranchSeparator, "/")
 	return fmt.Sprintf("%sdeps%s", branchPrefix, branchSeparator)
 }
 
-func GitRemote() string {
-	cmd := exec.Command("git", "remote", "get-url", "origin")
-	remote, err := cmd.CombinedOutput()
-	if err != nil {
-		panic(err)
+func setting(envKey, configured, defaultValue string) string {
+	if s := os.Getenv(envKey); s != "" {
+		return s
 	}
-	s := string(remote)
-	s = strings.TrimSpace(s)
-	return s
+	if configured != "" {
+		return configured
+	}
+	return defaultValue
+}
+
+// GitRemote is the url of the base remote, which the repo host and name come from
+func GitRemote() (string, error) {
+	return getBackend().RemoteURL(BaseRemoteName())
+}
+
+// PushRemote is the url of the remote that deps branches are pushed to
+func PushRemote() (string, error) {
+	return getBackend().RemoteURL(RemoteName())
 }
 
-func GitRemoteHostname() string {
-	remote := GitRemote()
+func GitRemoteHostname() (string, error) {
+	remote, err := GitRemote()
+	if err != nil {
+		return "", err
+	}
 	parsed, err := url.Parse(remote)
 	if err != nil {
-		panic(err)
+		return "", err
 	}
-	return parsed.Hostname()
+	return parsed.Hostname(), nil
 }
 
 func GitRemoteToHTTPS(original string) string {
@@ -95,159 +186,90 @@ func GitRemoteToHTTPS(original string) string {
 	return updated
 }
 
-func Clone(url, path string) {
-	if err := run("clone", url, path); err != nil {
-		panic(err)
-	}
+func Clone(url, path string) error {
+	return getBackend().Clone(url, path)
 }
 
-func BranchMatching(startsWith string) string {
-	branches := listBranches()
+func BranchMatching(startsWith string) (string, error) {
+	branches, err := getBackend().ListBranches()
+	if err != nil {
+		return "", err
+	}
 	for _, b := range branches {
 		if strings.HasPrefix(b, startsWith) {
-			return b
+			return b, nil
 		}
 	}
-	return ""
+	return "", nil
 }
 
-func CurrentRef() string {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	out, err := cmd.CombinedOutput()
-
-	if err != nil {
-		panic(err)
-	}
-
-	return strings.TrimSpace(string(out))
+func CurrentRef() (string, error) {
+	return getBackend().CurrentRef()
 }
 
-func Add() {
-	if err := run("add", "."); err != nil {
-		panic(err)
-	}
+func Add() error {
+	return getBackend().Add()
 }
 
-func Unstage() {
-	if err := run("reset", "."); err != nil {
-		panic(err)
-	}
+func Unstage() error {
+	return getBackend().Unstage()
 }
 
-func Commit(message string) {
-	if err := run("commit", "-m", message); err != nil {
-		panic(err)
-	}
+func Commit(message string) error {
+	return getBackend().Commit(message)
 }
 
-func Checkout(s string) {
-	if err := run("checkout", s); err != nil {
-		panic(err)
-	}
+func Checkout(s string) error {
+	return getBackend().Checkout(s)
 }
 
-func CheckoutLast() {
-	Checkout("-")
+func CheckoutLast() error {
+	return Checkout("-")
 }
 
-func ResetAndClean() {
-	if err := run("reset", "--hard"); err != nil {
-		panic(err)
-	}
-	if err := run("clean", "-df"); err != nil {
-		panic(err)
-	}
+func ResetAndClean() error {
+	return getBackend().ResetAndClean()
 }
 
-func Stash(message string) bool {
-	cmd := exec.Command("git", "stash", "save", "--include-untracked", message)
-	out, err := cmd.CombinedOutput()
-	outS := string(out)
-	println(outS)
-	if err != nil {
-		panic(err)
-	}
-	if strings.Contains(outS, "No local changes to save") {
-		return false
-	}
-	return true
+// Stash saves all local changes and reports whether there were any
+func Stash(message string) (bool, error) {
+	return getBackend().Stash(message)
 }
 
 func StashPop() error {
-	return run("stash", "pop")
+	return getBackend().StashPop()
 }
 
 func Pull() error {
-	return run("pull")
+	return getBackend().Pull()
 }
 
-func RenameBranch(old, new string) {
-	if err := run("branch", "-m", old, new); err != nil {
-		panic(err)
-	}
+func RenameBranch(old, new string) error {
+	return getBackend().RenameBranch(old, new)
 }
 
-func Fetch() {
-	if err := run("fetch"); err != nil {
-		panic(err)
-	}
+func Fetch() error {
+	return getBackend().Fetch()
 }
 
-func HasStagedChanges() bool {
-	cmd := exec.Command("git", "diff", "--name-only", "--staged")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		panic(err)
-	}
-	cleaned := strings.TrimSpace(string(out))
-	return cleaned != ""
+func HasStagedChanges() (bool, error) {
+	return getBackend().HasStagedChanges()
 }
 
-func IsDirty() bool {
-	cmd := exec.Command("git", "status", "--porcelain")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		panic(err)
-	}
-	cleaned := strings.TrimSpace(string(out))
-	return cleaned != ""
+func IsDirty() (bool, error) {
+	return getBackend().IsDirty()
 }
 
-func Status() string {
-	cmd := exec.Command("git", "status")
-	status, err := cmd.CombinedOutput()
-	if err != nil {
-		panic(err)
-	}
-	s := string(status)
-	return s
+func Status() (string, error) {
+	return getBackend().Status()
 }
 
-func run(args ...string) error {
-	cmdString := fmt.Sprintf("git %s", strings.Join(args, " "))
-	output.Debug(cmdString)
-	cmd := exec.Command("git", args...)
-
-	out := bytes.Buffer{}
-
-	if output.IsDebug() {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	} else {
-		cmd.Stdout = &out
-		cmd.Stderr = &out
-	}
-
-	if err := cmd.Run(); err != nil {
-
-		if !output.IsDebug() {
-			// Show more output if it wasn't showing already
-			println(cmdString)
-			println(out.String())
-		}
-
-		return err
-	}
+// SetUser configures the name and email used for commits in this repo
+func SetUser(name, email string) error {
+	return getBackend().SetUser(name, email)
+}
 
-	return nil
+// UserEmail is the email that commits are made with, empty if it isn't configured
+func UserEmail() (string, error) {
+	return getBackend().UserEmail()
 }
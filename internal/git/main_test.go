@@ -1,6 +1,8 @@
 package git
 
 import (
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -70,11 +72,34 @@ func TestGitRemoteToHTTPS(t *testing.T) {
 }
 
 func TestGitRemoteHostname(t *testing.T) {
-	if GitRemoteHostname() != "github.com" {
+	hostname, err := GitRemoteHostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostname != "github.com" {
 		t.Fail()
 	}
 }
 
+func TestError(t *testing.T) {
+	inTempRepo(t)
+
+	err := Checkout("deps-branch-that-does-not-exist")
+	gitErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected a *git.Error, got %T", err)
+	}
+	if gitErr.ExitCode == 0 || gitErr.ExitCode == -1 {
+		t.Errorf("unexpected exit code %d", gitErr.ExitCode)
+	}
+	if !strings.Contains(gitErr.Stderr, "deps-branch-that-does-not-exist") {
+		t.Errorf("stderr should be captured: %q", gitErr.Stderr)
+	}
+	if !strings.HasPrefix(gitErr.Error(), "git checkout deps-branch-that-does-not-exist: exit status") {
+		t.Error(gitErr.Error())
+	}
+}
+
 // func TestGetDepsBranches(t *testing.T) {
 // 	branches := GetDepsBranches()
 // 	for _, b := range branches {
@@ -82,3 +107,52 @@ func TestGitRemoteHostname(t *testing.T) {
 // 	}
 // 	t.Fail()
 // }
+
+func TestBranchNaming(t *testing.T) {
+	defer Configure("", "", "")
+
+	if name := GetBranchName("update-1"); name != "deps/update-1" {
+		t.Error(name)
+	}
+
+	if err := Configure("fork", "bot/", "-"); err != nil {
+		t.Fatal(err)
+	}
+	if name := GetBranchName("update-1"); name != "bot/deps-update-1" {
+		t.Error(name)
+	}
+	if !IsDepsBranch("bot/deps-update-1") || IsDepsBranch("deps/update-1") {
+		t.Error("deps branches should use the configured prefix")
+	}
+	if RemoteName() != "fork" {
+		t.Error(RemoteName())
+	}
+
+	if err := Configure("", "bot ", ""); err == nil {
+		t.Error("expected an error for an invalid branch prefix")
+	}
+
+	os.Setenv("DEPS_GIT_BRANCH_PREFIX", "env/")
+	defer os.Unsetenv("DEPS_GIT_BRANCH_PREFIX")
+	if name := GetBranchName("update-1"); name != "env/deps-update-1" {
+		t.Error(name)
+	}
+
+	os.Setenv("DEPS_GIT_BRANCH_PREFIX", "env..")
+	if err := Configure("", "bot/", ""); err == nil {
+		t.Error("expected an error for an invalid branch prefix from the environment")
+	}
+}
+
+func TestCommitArgs(t *testing.T) {
+	t.Setenv("DEPS_GIT_SIGNING_KEY", "")
+	if args := strings.Join(commitArgs("Update"), " "); args != "commit -m Update" {
+		t.Errorf("unexpected args %s", args)
+	}
+
+	t.Setenv("DEPS_GIT_SIGNING_KEY", "~/.ssh/id_ed25519.pub")
+	t.Setenv("DEPS_GIT_SIGNING_FORMAT", "ssh")
+	if args := strings.Join(commitArgs("Update"), " "); args != "-c gpg.format=ssh -c user.signingkey=~/.ssh/id_ed25519.pub -c commit.gpgsign=true commit -m Update" {
+		t.Errorf("unexpected args %s", args)
+	}
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Names for DEPS_GIT_SIGNING_FORMAT
+const (
+	SigningFormatGPG = "gpg"
+	SigningFormatSSH = "ssh"
+)
+
+var errSigningNeedsExec = errors.New("signed commits need the git executable, they aren't supported by the in-process git backend")
+
+// signingKey is the GPG key ID or SSH key (a path, or "key::..." to use
+// a key from the ssh-agent) that commits are signed with, if any
+func signingKey() string {
+	return os.Getenv("DEPS_GIT_SIGNING_KEY")
+}
+
+func signingFormat() string {
+	return setting("DEPS_GIT_SIGNING_FORMAT", "", SigningFormatGPG)
+}
+
+// signingArgs are the git options for signing a commit,
+// which are empty if signing isn't configured
+func signingArgs() []string {
+	key := signingKey()
+	if key == "" {
+		return []string{}
+	}
+	return []string{
+		"-c", "gpg.format=" + signingFormat(),
+		"-c", "user.signingkey=" + key,
+		"-c", "commit.gpgsign=true",
+	}
+}
+
+// commitArgs commits the staged changes, signing the commit if configured
+func commitArgs(message string) []string {
+	return append(signingArgs(), "commit", "-m", message)
+}
+
+// CheckSigning makes sure that commits can be signed with the configured
+// key, by signing a commit that nothing points to
+func CheckSigning() error {
+	if signingKey() == "" {
+		return nil
+	}
+
+	if format := signingFormat(); format != SigningFormatGPG && format != SigningFormatSSH {
+		return fmt.Errorf("Unknown DEPS_GIT_SIGNING_FORMAT \"%s\", expected \"%s\" or \"%s\"", format, SigningFormatGPG, SigningFormatSSH)
+	}
+
+	if !usesExec() {
+		return errSigningNeedsExec
+	}
+
+	// the git user may not be configured yet, but it's only a test
+	args := append(signingArgs(), "-c", "user.name=deps", "-c", "user.email=bot@dependencies.io")
+	args = append(args, "commit-tree", "-S", "-p", "HEAD", "-m", "Check commit signing", "HEAD^{tree}")
+	if _, err := capture(args...); err != nil {
+		return fmt.Errorf("Unable to sign commits with DEPS_GIT_SIGNING_KEY: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dropseed/deps/internal/output"
+)
+
+var errWorktreesNeedExec = errors.New("git worktrees need the git executable, they aren't supported by the in-process git backend")
+
+// worktreesLock keeps worktrees from being added or removed at the same
+// time, which would race on the locks in the shared .git directory
+var worktreesLock sync.Mutex
+
+// Worktree is a temporary checkout of the repo in another directory,
+// so changes can be made without touching the current one
+type Worktree struct {
+	Path string
+}
+
+// AddWorktree checks out ref (detached) in a new temporary directory
+func AddWorktree(ref string) (*Worktree, error) {
+	if !usesExec() {
+		return nil, errWorktreesNeedExec
+	}
+	dir, err := ioutil.TempDir("", "deps-worktree-")
+	if err != nil {
+		return nil, err
+	}
+	worktreesLock.Lock()
+	defer worktreesLock.Unlock()
+	if err := run("worktree", "add", "--detach", dir, ref); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &Worktree{Path: dir}, nil
+}
+
+// AddBranchWorktree checks out a branch in a new temporary directory,
+// creating (or resetting) the branch at startPoint if one is given
+func AddBranchWorktree(branch, startPoint string) (*Worktree, error) {
+	if !usesExec() {
+		return nil, errWorktreesNeedExec
+	}
+	dir, err := ioutil.TempDir("", "deps-worktree-")
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"worktree", "add", dir, branch}
+	if startPoint != "" {
+		args = []string{"worktree", "add", "-B", branch, dir, startPoint}
+	}
+	worktreesLock.Lock()
+	defer worktreesLock.Unlock()
+	if err := run(args...); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &Worktree{Path: dir}, nil
+}
+
+// IsDirty checks for any uncommitted changes in the worktree
+func (w *Worktree) IsDirty() (bool, error) {
+	out, err := execute(w.Path, []string{"status", "--porcelain"})
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// Commit stages and commits everything in the worktree
+func (w *Worktree) Commit(message string) error {
+	if err := w.run("add", "--all"); err != nil {
+		return err
+	}
+	return w.run(commitArgs(message)...)
+}
+
+// Rebase the worktree branch onto another, a conflict
+// aborts the rebase and returns an error
+func (w *Worktree) Rebase(onto string) error {
+	return rebase(w.Path, onto)
+}
+
+// ForcePushBranch replaces the branch on the remote, but only if it
+// hasn't changed since it was last fetched
+func (w *Worktree) ForcePushBranch(branchName string) error {
+	return w.run("push", "--force-with-lease", "--set-upstream", RemoteName(), branchName)
+}
+
+// PushBranch pushes the branch to the remote
+func (w *Worktree) PushBranch(branchName string) error {
+	return w.run("push", "--set-upstream", RemoteName(), branchName)
+}
+
+// Diff shows all of the changes in the worktree, including new files
+func (w *Worktree) Diff() (string, error) {
+	if err := w.run("add", "--all"); err != nil {
+		return "", err
+	}
+	return execute(w.Path, []string{"diff", "--cached"})
+}
+
+// Remove deletes the worktree and its directory
+func (w *Worktree) Remove() error {
+	worktreesLock.Lock()
+	defer worktreesLock.Unlock()
+	if err := run("worktree", "remove", "--force", w.Path); err != nil {
+		return err
+	}
+	return os.RemoveAll(w.Path)
+}
+
+func (w *Worktree) run(args ...string) error {
+	output.Debug("git %s (in %s)", strings.Join(args, " "), w.Path)
+	if out, err := execute(w.Path, args); err != nil {
+		println(out)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+// inTempRepo runs the rest of the test from a new repo with one commit,
+// so that nothing is left behind in this one if the test fails
+func inTempRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.name", "deps"},
+		{"config", "user.email", "bot@dependencies.io"},
+		{"commit", "--allow-empty", "-m", "Initial commit"},
+	} {
+		if err := run(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWorktree(t *testing.T) {
+	inTempRepo(t)
+
+	worktree, err := AddWorktree("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path.Join(worktree.Path, "deps-worktree-test.txt"), []byte("test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := worktree.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+++ b/deps-worktree-test.txt") {
+		t.Error(diff)
+	}
+
+	if err := worktree.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(worktree.Path); !os.IsNotExist(err) {
+		t.Error("worktree directory should be removed")
+	}
+}
+
+func TestBranchWorktree(t *testing.T) {
+	inTempRepo(t)
+
+	branch := "deps-worktree-test"
+	worktree, err := AddBranchWorktree(branch, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dirty, err := worktree.IsDirty(); err != nil || dirty {
+		t.Errorf("new worktree should be clean: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path.Join(worktree.Path, "deps-worktree-test.txt"), []byte("test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if dirty, err := worktree.IsDirty(); err != nil || !dirty {
+		t.Errorf("worktree should be dirty: %v", err)
+	}
+
+	if err := worktree.Remove(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorktreeRebaseConflict(t *testing.T) {
+	inTempRepo(t)
+
+	branches := map[string]*Worktree{}
+	for _, branch := range []string{"deps-rebase-test-a", "deps-rebase-test-b"} {
+		worktree, err := AddBranchWorktree(branch, "HEAD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer worktree.Remove()
+
+		if err := ioutil.WriteFile(path.Join(worktree.Path, "deps-rebase-test.txt"), []byte(branch+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := worktree.Commit("Test " + branch); err != nil {
+			t.Fatal(err)
+		}
+		branches[branch] = worktree
+	}
+
+	worktree := branches["deps-rebase-test-b"]
+	err := worktree.Rebase("deps-rebase-test-a")
+	if _, ok := err.(*Error); !ok {
+		t.Fatalf("expected a *git.Error, got %v", err)
+	}
+
+	// the rebase should have been aborted
+	if dirty, err := worktree.IsDirty(); err != nil || dirty {
+		t.Errorf("worktree should be clean after the rebase is aborted: %v", err)
+	}
+}
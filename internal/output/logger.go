@@ -0,0 +1,105 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Logger buffers output so that work done concurrently can be printed
+// in one piece when it is finished. A nil *Logger prints directly.
+type Logger struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// Write makes the Logger usable as the output of commands
+func (l *Logger) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buffer.Write(p)
+}
+
+// Output is where command output should be sent
+func (l *Logger) Output() io.Writer {
+	if l == nil {
+		return Writer
+	}
+	return l
+}
+
+// Flush prints everything that has been buffered so far
+func (l *Logger) Flush() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	Writer.Write(l.buffer.Bytes())
+	l.buffer.Reset()
+}
+
+func (l *Logger) Event(f string, args ...interface{}) {
+	if l == nil {
+		Event(f, args...)
+		return
+	}
+	isTerm := terminal.IsTerminal(int(os.Stdout.Fd()))
+	prefix := ""
+	if isTerm {
+		prefix = color.New(color.FgMagenta).Sprint("> ")
+	}
+	line := fmt.Sprintf(f, args...)
+	if isTerm && IsDebug() {
+		line = color.New(color.Bold).Sprint(line)
+	}
+	fmt.Fprintln(l, prefix+line)
+}
+
+func (l *Logger) Debug(f string, args ...interface{}) {
+	if l == nil {
+		Debug(f, args...)
+		return
+	}
+	if !IsDebug() {
+		return
+	}
+	prefix := ""
+	if terminal.IsTerminal(int(os.Stdout.Fd())) {
+		prefix = color.New(color.FgCyan).Sprint("> ")
+	}
+	fmt.Fprintln(l, prefix+fmt.Sprintf(f, args...))
+}
+
+func (l *Logger) Warning(f string, args ...interface{}) {
+	if l == nil {
+		Warning(f, args...)
+		return
+	}
+	fmt.Fprintln(l, color.New(color.FgYellow).Sprintf(f, args...))
+}
+
+func (l *Logger) Error(f string, args ...interface{}) {
+	if l == nil {
+		Error(f, args...)
+		return
+	}
+	fmt.Fprintln(l, color.New(color.FgRed).Sprintf(f, args...))
+}
+
+func (l *Logger) Success(f string, args ...interface{}) {
+	if l == nil {
+		Success(f, args...)
+		return
+	}
+	fmt.Fprintln(l, color.New(color.FgGreen).Sprintf(f, args...))
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	original := Writer
+	defer func() { Writer = original }()
+
+	out := &bytes.Buffer{}
+	Writer = out
+
+	first := NewLogger()
+	second := NewLogger()
+	first.Warning("one")
+	second.Warning("two")
+	first.Warning("three")
+
+	if out.Len() != 0 {
+		t.Fatal("output should be buffered")
+	}
+
+	second.Flush()
+	first.Flush()
+
+	if out.String() != "two\none\nthree\n" {
+		t.Error(out.String())
+	}
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/fatih/color"
@@ -10,6 +11,15 @@ import (
 
 var Verbosity = 0
 
+// Writer is where all output goes
+var Writer io.Writer = os.Stdout
+
+// UseStderr sends all output to stderr, so that data can be printed to stdout
+func UseStderr() {
+	Writer = os.Stderr
+	color.Output = os.Stderr
+}
+
 func IsDebug() bool {
 	return Verbosity > 0
 }
@@ -24,7 +34,7 @@ func Event(f string, args ...interface{}) {
 	if isTerm && IsDebug() {
 		color.Set(color.Bold)
 	}
-	fmt.Printf(f+"\n", args...)
+	fmt.Fprintf(Writer, f+"\n", args...)
 	if isTerm && IsDebug() {
 		color.Unset()
 	}
@@ -39,23 +49,23 @@ func Debug(f string, args ...interface{}) {
 		print("> ")
 		color.Unset()
 	}
-	fmt.Printf(f+"\n", args...)
+	fmt.Fprintf(Writer, f+"\n", args...)
 }
 
 func Warning(f string, args ...interface{}) {
 	color.Set(color.FgYellow)
-	fmt.Printf(f+"\n", args...)
+	fmt.Fprintf(Writer, f+"\n", args...)
 	color.Unset()
 }
 
 func Error(f string, args ...interface{}) {
 	color.Set(color.FgRed)
-	fmt.Printf(f+"\n", args...)
+	fmt.Fprintf(Writer, f+"\n", args...)
 	color.Unset()
 }
 
 func Success(f string, args ...interface{}) {
 	color.Set(color.FgGreen)
-	fmt.Printf(f+"\n", args...)
+	fmt.Fprintf(Writer, f+"\n", args...)
 	color.Unset()
 }
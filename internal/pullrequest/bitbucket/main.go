@@ -24,6 +24,9 @@ type PullRequest struct {
 	ProjectAPIURL string
 	APIUsername   string
 	APIPassword   string
+
+	// URL is set once the pull request has been created
+	URL string
 }
 
 func NewPullRequest(base string, head string, deps *schema.Dependencies, cfg *config.Dependency) (*PullRequest, error) {
@@ -35,7 +38,7 @@ func NewPullRequest(base string, head string, deps *schema.Dependencies, cfg *co
 	return &PullRequest{
 		Base:          base,
 		Head:          head,
-		Title:         schemaext.TitleForDeps(deps),
+		Title:         schemaext.TitleForDeps(deps, cfg.LockfileUpdates.Split),
 		Body:          schemaext.DescriptionForDeps(deps),
 		Dependencies:  deps,
 		Config:        cfg,
@@ -49,7 +52,29 @@ func (pr *PullRequest) GetSetting(name string) interface{} {
 	return pr.Config.GetSettingForSchema(name, pr.Dependencies)
 }
 
+func (pr *PullRequest) GetURL() string {
+	return pr.URL
+}
+
+func htmlURLFromBody(body string) string {
+	var data struct {
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return ""
+	}
+	return data.Links.HTML.Href
+}
+
 func (pr *PullRequest) request(verb string, url string, input []byte) (*http.Response, string, error) {
+	return apiRequest(pr.APIUsername, pr.APIPassword, verb, url, input)
+}
+
+func apiRequest(username string, password string, verb string, url string, input []byte) (*http.Response, string, error) {
 	client := &http.Client{}
 
 	req, err := http.NewRequest(verb, url, bytes.NewBuffer(input))
@@ -57,7 +82,7 @@ func (pr *PullRequest) request(verb string, url string, input []byte) (*http.Res
 		return nil, "", err
 	}
 
-	req.SetBasicAuth(pr.APIUsername, pr.APIPassword)
+	req.SetBasicAuth(username, password)
 	req.Header.Add("User-Agent", "deps")
 	req.Header.Set("Content-Type", "application/json")
 
@@ -89,6 +114,7 @@ func (pr *PullRequest) CreateOrUpdate() error {
 
 	if resp.StatusCode == 201 {
 		output.Event("Successfully created Bitbucket pull request for %v\n", pr.ProjectAPIURL)
+		pr.URL = htmlURLFromBody(body)
 		return nil
 	}
 
@@ -1,7 +1,12 @@
 package bitbucket
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/dropseed/deps/internal/git"
 )
 
 type BitbucketRepo struct {
@@ -23,8 +28,75 @@ func (repo *BitbucketRepo) CheckRequirements() error {
 	if repo.apiUsername == "" {
 		return errors.New("Unable to find Bitbucket API username.\n\nVisit https://docs.dependencies.io/bitbucket for more information.")
 	}
+	return git.CheckSigning()
+}
+
+func (repo *BitbucketRepo) Autoconfigure() error {
 	return nil
 }
 
-func (repo *BitbucketRepo) Autoconfigure() {
+// CloseStale comments on and declines the open pull requests from a branch
+func (repo *BitbucketRepo) CloseStale(branch string, comment string) ([]string, error) {
+	apiURL, err := getProjectAPIURL()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("source.branch.name=\"%s\" AND state=\"OPEN\"", branch)
+	resp, body, err := apiRequest(repo.apiUsername, repo.apiPassword, "GET", apiURL+"/pullrequests?q="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("List pull requests API returned %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Values []struct {
+			ID    int `json:"id"`
+			Links struct {
+				HTML struct {
+					Href string `json:"href"`
+				} `json:"html"`
+			} `json:"links"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return nil, err
+	}
+
+	closed := []string{}
+
+	for _, pr := range data.Values {
+		prURL := fmt.Sprintf("%s/pullrequests/%d", apiURL, pr.ID)
+		htmlURL := pr.Links.HTML.Href
+
+		commentData, _ := json.Marshal(map[string]interface{}{
+			"content": map[string]string{"raw": comment},
+		})
+		resp, body, err := apiRequest(repo.apiUsername, repo.apiPassword, "POST", prURL+"/comments", commentData)
+		if err != nil {
+			return closed, err
+		}
+		if resp.StatusCode != 201 {
+			return closed, fmt.Errorf("Failed to comment on pull request %s: %s", htmlURL, body)
+		}
+
+		resp, body, err = apiRequest(repo.apiUsername, repo.apiPassword, "POST", prURL+"/decline", nil)
+		if err != nil {
+			return closed, err
+		}
+		if resp.StatusCode != 200 {
+			return closed, fmt.Errorf("Failed to decline pull request %s: %s", htmlURL, body)
+		}
+
+		closed = append(closed, htmlURL)
+	}
+
+	return closed, nil
+}
+
+// AddLabel does nothing, Bitbucket pull requests don't have labels
+func (repo *BitbucketRepo) AddLabel(branch string, label string) ([]string, error) {
+	return nil, nil
 }
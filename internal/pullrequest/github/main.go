@@ -26,9 +26,13 @@ type PullRequest struct {
 	Config       *config.Dependency
 
 	RepoOwnerName string
+	HeadOwnerName string
 	RepoName      string
 	RepoFullName  string
 	APIToken      string
+
+	// URL is set once the pull request has been created or found
+	URL string
 }
 
 func NewPullRequest(base string, head string, deps *schema.Dependencies, cfg *config.Dependency) (*PullRequest, error) {
@@ -43,11 +47,12 @@ func NewPullRequest(base string, head string, deps *schema.Dependencies, cfg *co
 	return &PullRequest{
 		Base:          base,
 		Head:          head,
-		Title:         schemaext.TitleForDeps(deps),
+		Title:         schemaext.TitleForDeps(deps, cfg.LockfileUpdates.Split),
 		Body:          schemaext.DescriptionForDeps(deps),
 		Dependencies:  deps,
 		Config:        cfg,
 		RepoOwnerName: owner,
+		HeadOwnerName: getHeadOwner(owner),
 		RepoName:      repo,
 		RepoFullName:  fullName,
 		APIToken:      getAPIToken(),
@@ -58,7 +63,15 @@ func (pr *PullRequest) GetSetting(name string) interface{} {
 	return pr.Config.GetSettingForSchema(name, pr.Dependencies)
 }
 
+func (pr *PullRequest) GetURL() string {
+	return pr.URL
+}
+
 func (pr *PullRequest) request(verb string, url string, input []byte) (*http.Response, string, error) {
+	return apiRequest(pr.APIToken, verb, url, input)
+}
+
+func apiRequest(token string, verb string, url string, input []byte) (*http.Response, string, error) {
 	client := &http.Client{}
 
 	req, err := http.NewRequest(verb, url, bytes.NewBuffer(input))
@@ -66,7 +79,7 @@ func (pr *PullRequest) request(verb string, url string, input []byte) (*http.Res
 		return nil, "", err
 	}
 
-	req.Header.Add("Authorization", "token "+pr.APIToken)
+	req.Header.Add("Authorization", "token "+token)
 	req.Header.Add("User-Agent", "deps")
 	req.Header.Set("Content-Type", "application/json")
 
@@ -81,8 +94,12 @@ func (pr *PullRequest) request(verb string, url string, input []byte) (*http.Res
 }
 
 func (pr *PullRequest) pullsURL() string {
+	return pullsURL(pr.RepoFullName)
+}
+
+func pullsURL(repoFullName string) string {
 	apiBase := "https://api.github.com" // or from setting/env
-	return fmt.Sprintf("%s/repos/%s/pulls", apiBase, pr.RepoFullName)
+	return fmt.Sprintf("%s/repos/%s/pulls", apiBase, repoFullName)
 }
 
 func (pr *PullRequest) getCreateJSONData() ([]byte, error) {
@@ -99,7 +116,7 @@ func (pr *PullRequest) getCreateJSONData() ([]byte, error) {
 
 	pullrequestMap := map[string]string{
 		"title": pr.Title,
-		"head":  pr.Head,
+		"head":  headRef(pr.HeadOwnerName, pr.RepoOwnerName, pr.Head),
 		"base":  base,
 		"body":  body,
 	}
@@ -138,7 +155,7 @@ func (pr *PullRequest) createPR() (map[string]interface{}, error) {
 }
 
 func (pr *PullRequest) getExisting() (map[string]interface{}, error) {
-	params := fmt.Sprintf("?head=%s:%s&base=%s", pr.RepoOwnerName, pr.Head, pr.Base)
+	params := fmt.Sprintf("?head=%s:%s&base=%s", pr.HeadOwnerName, pr.Head, pr.Base)
 	resp, body, err := pr.request("GET", pr.pullsURL()+params, nil)
 	if err != nil {
 		return nil, err
@@ -190,6 +207,8 @@ func (pr *PullRequest) CreateOrUpdate() error {
 	issueTitle, _ := data["title"].(string)
 	issueBody, _ := data["body"].(string)
 
+	pr.URL = htmlURL
+
 	if labels != nil || assignees != nil || milestone != nil || pr.Title != issueTitle || pr.Body != issueBody {
 		issueMap := make(map[string]interface{})
 
@@ -1,10 +1,13 @@
 package github
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/dropseed/deps/internal/git"
 	"github.com/dropseed/deps/internal/output"
@@ -24,19 +27,110 @@ func (repo *GitHubRepo) CheckRequirements() error {
 	if repo.apiToken == "" {
 		return errors.New("Unable to find GitHub API token.\n\nVisit https://docs.dependencies.io/github for more information.")
 	}
-	return nil
+	return git.CheckSigning()
 }
 
-func (repo *GitHubRepo) Autoconfigure() {
+func (repo *GitHubRepo) Autoconfigure() error {
 	output.Debug("Writing GitHub token to ~/.netrc")
-	hostname := git.GitRemoteHostname()
+	hostname, err := git.GitRemoteHostname()
+	if err != nil {
+		return err
+	}
 	echo := fmt.Sprintf("echo -e \"machine %s\n  login x-access-token\n  password %s\" >> ~/.netrc", hostname, repo.apiToken)
 	cmd := exec.Command("sh", "-c", echo)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		panic(err)
+	return cmd.Run()
+}
+
+type openPull struct {
+	URL         string `json:"url"`
+	HTMLURL     string `json:"html_url"`
+	IssueURL    string `json:"issue_url"`
+	CommentsURL string `json:"comments_url"`
+}
+
+// openPulls lists the open pull requests from a branch
+func (repo *GitHubRepo) openPulls(branch string) ([]openPull, error) {
+	fullName, err := getRepoFullName()
+	if err != nil {
+		return nil, err
+	}
+	owner := getHeadOwner(strings.Split(fullName, "/")[0])
+
+	params := fmt.Sprintf("?state=open&head=%s", url.QueryEscape(owner+":"+branch))
+	resp, body, err := apiRequest(repo.apiToken, "GET", pullsURL(fullName)+params, nil)
+	if err != nil {
+		return nil, err
 	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("List pull requests API returned %d", resp.StatusCode)
+	}
+
+	var pulls []openPull
+	if err := json.Unmarshal([]byte(body), &pulls); err != nil {
+		return nil, err
+	}
+	return pulls, nil
+}
+
+// CloseStale comments on and closes the open pull requests from a branch
+func (repo *GitHubRepo) CloseStale(branch string, comment string) ([]string, error) {
+	pulls, err := repo.openPulls(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	closed := []string{}
+
+	for _, pull := range pulls {
+		commentData, _ := json.Marshal(map[string]string{"body": comment})
+		resp, body, err := apiRequest(repo.apiToken, "POST", pull.CommentsURL, commentData)
+		if err != nil {
+			return closed, err
+		}
+		if resp.StatusCode != 201 {
+			return closed, fmt.Errorf("Failed to comment on pull request %s: %s", pull.HTMLURL, body)
+		}
+
+		closeData, _ := json.Marshal(map[string]string{"state": "closed"})
+		resp, body, err = apiRequest(repo.apiToken, "PATCH", pull.URL, closeData)
+		if err != nil {
+			return closed, err
+		}
+		if resp.StatusCode != 200 {
+			return closed, fmt.Errorf("Failed to close pull request %s: %s", pull.HTMLURL, body)
+		}
+
+		closed = append(closed, pull.HTMLURL)
+	}
+
+	return closed, nil
+}
+
+// AddLabel adds a label to the open pull requests from a branch
+func (repo *GitHubRepo) AddLabel(branch string, label string) ([]string, error) {
+	pulls, err := repo.openPulls(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	labeled := []string{}
+
+	for _, pull := range pulls {
+		labelData, _ := json.Marshal(map[string][]string{"labels": []string{label}})
+		resp, body, err := apiRequest(repo.apiToken, "POST", pull.IssueURL+"/labels", labelData)
+		if err != nil {
+			return labeled, err
+		}
+		if resp.StatusCode != 200 {
+			return labeled, fmt.Errorf("Failed to label pull request %s: %s", pull.HTMLURL, body)
+		}
+
+		labeled = append(labeled, pull.HTMLURL)
+	}
+
+	return labeled, nil
 }
 
 // func (repo *GitHubRepo) NewPullrequest(deps *schema.Dependencies, baseBranch string) *PullRequest {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/bradleyfalzon/ghinstallation"
 	"github.com/dropseed/deps/internal/git"
@@ -42,13 +43,42 @@ func getRepoFullName() (string, error) {
 		return fmt.Sprintf("%s/%s", s, os.Getenv("CIRCLE_PROJECT_REPONAME")), nil
 	}
 
-	if s := getRepoFullNameFromRemote(git.GitRemote()); s != "" {
+	remote, err := git.GitRemote()
+	if err != nil {
+		return "", err
+	}
+	if s := getRepoFullNameFromRemote(remote); s != "" {
 		return s, nil
 	}
 
 	return "", errors.New("Unable to find GitHub repo full name")
 }
 
+// getHeadOwner is the owner of the repo that deps branches are pushed to,
+// which is a fork when it differs from the base repo owner
+func getHeadOwner(baseOwner string) string {
+	if git.RemoteName() == git.BaseRemoteName() {
+		return baseOwner
+	}
+	remote, err := git.PushRemote()
+	if err != nil {
+		return baseOwner
+	}
+	if s := getRepoFullNameFromRemote(remote); s != "" {
+		return strings.Split(s, "/")[0]
+	}
+	return baseOwner
+}
+
+// headRef is how the GitHub API refers to a branch, which needs
+// the owner if it's on a fork
+func headRef(headOwner, baseOwner, branch string) string {
+	if headOwner != baseOwner {
+		return headOwner + ":" + branch
+	}
+	return branch
+}
+
 func getRepoFullNameFromRemote(remote string) string {
 	pattern := regexp.MustCompile("([a-zA-Z0-9_-]+\\/[a-zA-Z0-9_-]+)(\\.git)?\\/?$")
 	matches := pattern.FindStringSubmatch(remote)
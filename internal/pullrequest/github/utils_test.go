@@ -1,6 +1,16 @@
 package github
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dropseed/deps/internal/config"
+	"github.com/dropseed/deps/internal/git"
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
 
 func TestNoopDereference(t *testing.T) {
 	body := "hey this is normal\n\nwith newlines"
@@ -31,3 +41,61 @@ func TestRepoNameFromRemote(t *testing.T) {
 		t.Error(name)
 	}
 }
+
+func TestForkRemote(t *testing.T) {
+	for _, key := range []string{"DEPS_GITHUB_REPOSITORY", "GITHUB_REPOSITORY", "TRAVIS_REPO_SLUG", "CIRCLE_PROJECT_USERNAME", "DEPS_GIT_BASE_REMOTE"} {
+		t.Setenv(key, "")
+	}
+
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, url := range map[string]string{
+		"origin": "https://github.com/dropseed/test.git",
+		"fork":   "git@github.com:deps-bot/test.git",
+	} {
+		if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git.SetBackend(git.NewInProcessBackend(repo))
+
+	t.Setenv("DEPS_GIT_REMOTE", "fork")
+
+	fullName, err := getRepoFullName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullName != "dropseed/test" {
+		t.Errorf("expected the base repo, got %s", fullName)
+	}
+
+	pr := &PullRequest{
+		Base:          "master",
+		Head:          "deps/update-test",
+		RepoOwnerName: "dropseed",
+		HeadOwnerName: getHeadOwner("dropseed"),
+		Config:        &config.Dependency{},
+	}
+	data, err := pr.getCreateJSONData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created map[string]string
+	if err := json.Unmarshal(data, &created); err != nil {
+		t.Fatal(err)
+	}
+	if created["head"] != "deps-bot:deps/update-test" {
+		t.Errorf("expected the fork owner in head, got %s", created["head"])
+	}
+
+	t.Setenv("DEPS_GIT_REMOTE", "origin")
+
+	if owner := getHeadOwner("dropseed"); owner != "dropseed" {
+		t.Errorf("expected the base owner without a fork, got %s", owner)
+	}
+	if head := headRef("dropseed", "dropseed", "deps/update-test"); head != "deps/update-test" {
+		t.Errorf("expected a bare branch without a fork, got %s", head)
+	}
+}
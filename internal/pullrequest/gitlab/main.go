@@ -27,6 +27,9 @@ type MergeRequest struct {
 
 	ProjectAPIURL string
 	APIToken      string
+
+	// URL is set once the merge request has been created or updated
+	URL string
 }
 
 func NewMergeRequest(base string, head string, deps *schema.Dependencies, cfg *config.Dependency) (*MergeRequest, error) {
@@ -38,7 +41,7 @@ func NewMergeRequest(base string, head string, deps *schema.Dependencies, cfg *c
 	return &MergeRequest{
 		Base:          base,
 		Head:          head,
-		Title:         schemaext.TitleForDeps(deps),
+		Title:         schemaext.TitleForDeps(deps, cfg.LockfileUpdates.Split),
 		Body:          schemaext.DescriptionForDeps(deps),
 		Dependencies:  deps,
 		Config:        cfg,
@@ -51,7 +54,24 @@ func (pr *MergeRequest) GetSetting(name string) interface{} {
 	return pr.Config.GetSettingForSchema(name, pr.Dependencies)
 }
 
+func (pr *MergeRequest) GetURL() string {
+	return pr.URL
+}
+
+func webURLFromBody(body string) string {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return ""
+	}
+	url, _ := data["web_url"].(string)
+	return url
+}
+
 func (pr *MergeRequest) request(verb string, url string, input []byte) (*http.Response, string, error) {
+	return apiRequest(pr.APIToken, verb, url, input)
+}
+
+func apiRequest(token string, verb string, url string, input []byte) (*http.Response, string, error) {
 	client := &http.Client{}
 
 	req, err := http.NewRequest(verb, url, bytes.NewBuffer(input))
@@ -59,7 +79,7 @@ func (pr *MergeRequest) request(verb string, url string, input []byte) (*http.Re
 		return nil, "", err
 	}
 
-	req.Header.Add("PRIVATE-TOKEN", pr.APIToken)
+	req.Header.Add("PRIVATE-TOKEN", token)
 	req.Header.Add("User-Agent", "deps")
 	req.Header.Set("Content-Type", "application/json")
 
@@ -91,6 +111,7 @@ func (pr *MergeRequest) CreateOrUpdate() error {
 
 	if resp.StatusCode == 201 {
 		output.Event("Successfully created GitLab merge request for %v\n", pr.ProjectAPIURL)
+		pr.URL = webURLFromBody(body)
 		return nil
 	} else if resp.StatusCode == 409 {
 		output.Event("Merge request already exists")
@@ -123,6 +144,7 @@ func (pr *MergeRequest) update(iid string, data []byte) error {
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf("Error updating merge request:\n\n%s", body)
 	}
+	pr.URL = webURLFromBody(body)
 	output.Success("Updated merge request %s", iid)
 	return nil
 }
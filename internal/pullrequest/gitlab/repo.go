@@ -1,8 +1,10 @@
 package gitlab
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"strings"
@@ -30,23 +32,123 @@ func (repo *GitLabRepo) CheckRequirements() error {
 	if repo.apiUsername == "" {
 		return errors.New("Unable to find GitLab API username.\n\nVisit https://docs.dependencies.io/gitlab for more information.")
 	}
-	return nil
+	return git.CheckSigning()
 }
 
-func (repo *GitLabRepo) Autoconfigure() {
-	remote := git.GitRemote()
+func (repo *GitLabRepo) Autoconfigure() error {
+	remote, err := git.PushRemote()
+	if err != nil {
+		return err
+	}
 	if strings.HasPrefix(remote, "https://gitlab-ci-token:") {
 		parts := strings.SplitN(remote, "@", 2)
 		keep := parts[1]
 		updatedRemote := fmt.Sprintf("https://%s:%s@%s", repo.apiUsername, repo.apiToken, keep)
 		maskedRemote := strings.Replace(updatedRemote, repo.apiToken, "*****", 1)
-		if cmd := exec.Command("git", "remote", "set-url", "origin", updatedRemote); cmd != nil {
-			output.Event("Autoconfigure: git remote set-url origin %s", maskedRemote)
+		if cmd := exec.Command("git", "remote", "set-url", git.RemoteName(), updatedRemote); cmd != nil {
+			output.Event("Autoconfigure: git remote set-url %s %s", git.RemoteName(), maskedRemote)
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
 			if err := cmd.Run(); err != nil {
-				panic(err)
+				return err
 			}
 		}
 	}
+	return nil
+}
+
+type openMergeRequest struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+// openMergeRequests lists the open merge requests from a branch
+func (repo *GitLabRepo) openMergeRequests(apiURL string, branch string) ([]openMergeRequest, error) {
+	params := fmt.Sprintf("?state=opened&source_branch=%s", url.QueryEscape(branch))
+	resp, body, err := apiRequest(repo.apiToken, "GET", apiURL+"/merge_requests"+params, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("List merge requests API returned %d", resp.StatusCode)
+	}
+
+	var mergeRequests []openMergeRequest
+	if err := json.Unmarshal([]byte(body), &mergeRequests); err != nil {
+		return nil, err
+	}
+	return mergeRequests, nil
+}
+
+// CloseStale comments on and closes the open merge requests from a branch
+func (repo *GitLabRepo) CloseStale(branch string, comment string) ([]string, error) {
+	apiURL, err := getProjectAPIURL()
+	if err != nil {
+		return nil, err
+	}
+
+	mergeRequests, err := repo.openMergeRequests(apiURL, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	closed := []string{}
+
+	for _, mr := range mergeRequests {
+		mrURL := fmt.Sprintf("%s/merge_requests/%d", apiURL, mr.IID)
+
+		noteData, _ := json.Marshal(map[string]string{"body": comment})
+		resp, body, err := apiRequest(repo.apiToken, "POST", mrURL+"/notes", noteData)
+		if err != nil {
+			return closed, err
+		}
+		if resp.StatusCode != 201 {
+			return closed, fmt.Errorf("Failed to comment on merge request %s: %s", mr.WebURL, body)
+		}
+
+		closeData, _ := json.Marshal(map[string]string{"state_event": "close"})
+		resp, body, err = apiRequest(repo.apiToken, "PUT", mrURL, closeData)
+		if err != nil {
+			return closed, err
+		}
+		if resp.StatusCode != 200 {
+			return closed, fmt.Errorf("Failed to close merge request %s: %s", mr.WebURL, body)
+		}
+
+		closed = append(closed, mr.WebURL)
+	}
+
+	return closed, nil
+}
+
+// AddLabel adds a label to the open merge requests from a branch
+func (repo *GitLabRepo) AddLabel(branch string, label string) ([]string, error) {
+	apiURL, err := getProjectAPIURL()
+	if err != nil {
+		return nil, err
+	}
+
+	mergeRequests, err := repo.openMergeRequests(apiURL, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	labeled := []string{}
+
+	for _, mr := range mergeRequests {
+		mrURL := fmt.Sprintf("%s/merge_requests/%d", apiURL, mr.IID)
+
+		labelData, _ := json.Marshal(map[string]string{"add_labels": label})
+		resp, body, err := apiRequest(repo.apiToken, "PUT", mrURL, labelData)
+		if err != nil {
+			return labeled, err
+		}
+		if resp.StatusCode != 200 {
+			return labeled, fmt.Errorf("Failed to label merge request %s: %s", mr.WebURL, body)
+		}
+
+		labeled = append(labeled, mr.WebURL)
+	}
+
+	return labeled, nil
 }
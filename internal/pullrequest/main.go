@@ -7,6 +7,7 @@ import (
 
 	"github.com/dropseed/deps/internal/config"
 	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
 	"github.com/dropseed/deps/internal/pullrequest/bitbucket"
 	"github.com/dropseed/deps/internal/pullrequest/github"
 	"github.com/dropseed/deps/internal/pullrequest/gitlab"
@@ -21,11 +22,19 @@ const BITBUCKET = "bitbucket"
 type PullrequestAdapter interface {
 	CreateOrUpdate() error
 	GetSetting(string) interface{}
+	// GetURL is the web URL, after CreateOrUpdate
+	GetURL() string
 }
 
 type RepoAdapter interface {
 	CheckRequirements() error
-	Autoconfigure()
+	Autoconfigure() error
+	// CloseStale comments on and closes the open pull requests from a branch,
+	// returning the URLs of the ones that were closed
+	CloseStale(branch string, comment string) ([]string, error)
+	// AddLabel labels the open pull requests from a branch (if the host
+	// supports labels), returning the URLs of the ones that were labeled
+	AddLabel(branch string, label string) ([]string, error)
 	// NewPullrequest(*schema.Dependencies, string) PullrequestAdapter
 }
 
@@ -71,7 +80,10 @@ func gitHost() string {
 		return override
 	}
 
-	remote := git.GitRemote()
+	remote, err := git.GitRemote()
+	if err != nil {
+		output.Debug("Unable to get the git remote: %v", err)
+	}
 
 	// TODO https://user:pass@
 
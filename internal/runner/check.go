@@ -0,0 +1,230 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dropseed/deps/internal/config"
+	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/pkg/schema"
+	"github.com/dropseed/deps/pkg/versioning"
+)
+
+// checkRow is a single dependency that has an update available
+type checkRow struct {
+	UpdateID   string
+	Path       string
+	Name       string
+	Current    string
+	Available  string
+	UpdateType versioning.UpdateType
+	ReleasedAt *time.Time
+	Transitive bool
+	// Fixes are advisories that affect the current version but not the available one
+	Fixes []string
+}
+
+// Check collects updates without making them, prints what is outdated
+// in the given format, and fails if the updates don't meet the policy
+func Check(types []string, policy *Policy, format string) error {
+	if !isValidCheckFormat(format) {
+		return fmt.Errorf("Unknown format \"%s\", must be one of: %s", format, strings.Join(CheckFormats, ", "))
+	}
+
+	if format != FormatTable {
+		// keep stdout clean for the data
+		output.UseStderr()
+	}
+
+	cfg, err := config.FindOrInfer()
+	if err != nil {
+		return err
+	}
+
+	if err := configureGit(cfg); err != nil {
+		return err
+	}
+
+	// collectors can change files (like lockfiles), so the repo
+	// is put back the way it was once they're done
+	dirty, err := git.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		output.Debug("Temporarily saving your uncommitted changes in a git stash")
+		stashed, err := git.Stash("Deps save before check")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if stashed {
+				output.Debug("Putting original uncommitted changes back")
+				if err := git.StashPop(); err != nil {
+					output.Error("Error putting stash back: %v", err)
+				}
+			}
+		}()
+	}
+
+	updates, err := collectUpdates(cfg, types)
+	if err := restoreAfterCollecting(); err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	rows := checkRowsForUpdates(updates)
+
+	switch format {
+	case FormatJSON:
+		err = printCheckJSON(updates)
+	case FormatMarkdown:
+		printCheckMarkdown(updates)
+	case FormatSARIF:
+		err = printCheckSARIF(rows)
+	default:
+		fmt.Println()
+		if len(rows) < 1 {
+			output.Success("Everything is up to date")
+		} else {
+			printCheckTable(rows)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if violations := policy.violations(updates, rows, time.Now()); len(violations) > 0 {
+		fmt.Fprintln(output.Writer)
+		for _, v := range violations {
+			output.Error("- %s", v)
+		}
+		return fmt.Errorf("%d policy violations", len(violations))
+	}
+
+	return nil
+}
+
+// restoreAfterCollecting removes any changes that the collectors made
+func restoreAfterCollecting() error {
+	dirty, err := git.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		output.Debug("Restoring the state of your repo before updates were collected")
+		return git.ResetAndClean()
+	}
+	return nil
+}
+
+func checkRowsForUpdates(updates Updates) []*checkRow {
+	rows := []*checkRow{}
+
+	for _, update := range updates.sorted() {
+		for path, lockfile := range update.dependencies.Lockfiles {
+			for name, updateType := range lockfile.UpdateTypes() {
+				current := lockfile.Current.Dependencies[name]
+				updated := lockfile.Updated.Dependencies[name]
+				rows = append(rows, &checkRow{
+					UpdateID:   update.id,
+					Path:       path,
+					Name:       name,
+					Current:    current.Version.Name,
+					Available:  updated.Version.Name,
+					UpdateType: updateType,
+					ReleasedAt: updated.Version.ReleasedAt,
+					Transitive: updated.IsTransitive,
+					Fixes:      fixedAdvisories(current.Version, updated.Version),
+				})
+			}
+		}
+
+		for path, manifest := range update.dependencies.Manifests {
+			for name, updated := range manifest.Updated.Dependencies {
+				row := &checkRow{
+					UpdateID:   update.id,
+					Path:       path,
+					Name:       name,
+					Available:  updated.Constraint,
+					UpdateType: manifest.UpdateType(name),
+				}
+				if current, found := manifest.Current.Dependencies[name]; found {
+					row.Current = current.Constraint
+					row.Fixes = fixedAdvisories(current.Version, updated.Version)
+				}
+				if updated.Version != nil {
+					row.ReleasedAt = updated.Version.ReleasedAt
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Path != rows[j].Path {
+			return rows[i].Path < rows[j].Path
+		}
+		return rows[i].Name < rows[j].Name
+	})
+
+	return rows
+}
+
+func printCheckTable(rows []*checkRow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join([]string{"PATH", "DEPENDENCY", "CURRENT", "AVAILABLE", "TYPE", "UPDATE"}, "\t"))
+
+	// transitive lockfile dependencies are summarized to keep the table short
+	transitive := map[string]map[string]int{}
+
+	for _, row := range rows {
+		if row.Transitive {
+			if transitive[row.Path] == nil {
+				transitive[row.Path] = map[string]int{}
+			}
+			transitive[row.Path][row.UpdateID]++
+			continue
+		}
+		fmt.Fprintln(w, strings.Join([]string{row.Path, row.Name, row.Current, row.Available, string(row.UpdateType), row.UpdateID}, "\t"))
+	}
+
+	paths := make([]string, 0, len(transitive))
+	for path := range transitive {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		updateIDs := make([]string, 0, len(transitive[path]))
+		for updateID := range transitive[path] {
+			updateIDs = append(updateIDs, updateID)
+		}
+		sort.Strings(updateIDs)
+		for _, updateID := range updateIDs {
+			count := transitive[path][updateID]
+			fmt.Fprintln(w, strings.Join([]string{path, fmt.Sprintf("(%d transitive)", count), "", "", "", updateID}, "\t"))
+		}
+	}
+
+	w.Flush()
+}
+
+func fixedAdvisories(current, updated *schema.Version) []string {
+	if current == nil || updated == nil {
+		return nil
+	}
+	fixed := []string{}
+	for _, advisory := range current.Advisories {
+		if !containsString(updated.Advisories, advisory) {
+			fixed = append(fixed, advisory)
+		}
+	}
+	return fixed
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dropseed/deps/internal/sarif"
+	"github.com/dropseed/deps/internal/schemaext"
+	"github.com/dropseed/deps/pkg/schema"
+	"github.com/dropseed/deps/pkg/versioning"
+)
+
+// Formats for deps check
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatSARIF    = "sarif"
+)
+
+var CheckFormats = []string{FormatTable, FormatJSON, FormatMarkdown, FormatSARIF}
+
+func isValidCheckFormat(format string) bool {
+	return containsString(CheckFormats, format)
+}
+
+type checkJSONUpdate struct {
+	ID           string               `json:"id"`
+	Title        string               `json:"title"`
+	Dependencies *schema.Dependencies `json:"dependencies"`
+}
+
+func printCheckJSON(updates Updates) error {
+	out := struct {
+		Updates []*checkJSONUpdate `json:"updates"`
+	}{
+		Updates: []*checkJSONUpdate{},
+	}
+	for _, update := range updates.sorted() {
+		out.Updates = append(out.Updates, &checkJSONUpdate{
+			ID:           update.id,
+			Title:        update.title,
+			Dependencies: update.dependencies,
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func printCheckMarkdown(updates Updates) {
+	if len(updates) < 1 {
+		fmt.Println("Everything is up to date")
+		return
+	}
+
+	sections := []string{}
+	for _, update := range updates.sorted() {
+		sections = append(sections, fmt.Sprintf("## %s (`%s`)\n\n%s", update.title, update.id, schemaext.SummaryForDeps(update.dependencies)))
+	}
+	fmt.Printf("# %d dependency updates\n\n%s\n", len(updates), strings.Join(sections, "\n\n"))
+}
+
+func printCheckSARIF(rows []*checkRow) error {
+	log := sarif.NewLog("deps", "https://www.dependencies.io/")
+
+	for _, row := range rows {
+		if len(row.Fixes) > 0 {
+			log.AddRule("vulnerable-dependency", "An update is available that fixes a security advisory")
+			log.AddResult("vulnerable-dependency", sarif.LevelError, fmt.Sprintf("%s %s is affected by %s, update to %s", row.Name, row.Current, strings.Join(row.Fixes, ", "), row.Available), row.Path)
+			continue
+		}
+
+		ruleID := fmt.Sprintf("%s-update", row.UpdateType)
+		level := sarif.LevelNote
+		if row.UpdateType == versioning.Major || row.UpdateType == versioning.Unknown {
+			level = sarif.LevelWarning
+		}
+		log.AddRule(ruleID, fmt.Sprintf("A %s update is available", row.UpdateType))
+		log.AddResult(ruleID, level, fmt.Sprintf("%s can be updated from %s to %s", row.Name, row.Current, row.Available), row.Path)
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -11,56 +11,103 @@ import (
 	"github.com/dropseed/deps/internal/git"
 	"github.com/dropseed/deps/internal/output"
 	"github.com/dropseed/deps/internal/pullrequest"
-	"github.com/dropseed/deps/internal/schemaext"
 )
 
 type updateResult struct {
-	update *Update
-	err    error
+	update         *Update
+	err            error
+	category       string
+	status         string
+	reason         string
+	pullrequestURL string
+	startedAt      time.Time
+	duration       time.Duration
 }
 
 func CI(autoconfigure bool, types []string) error {
+	r := newReport("ci")
+	return r.finish(runCI(autoconfigure, types, r))
+}
 
-	api, err := billing.NewAPI()
-	if err != nil {
-		return err
+func runCI(autoconfigure bool, types []string, r *report) error {
+
+	var api *billing.API
+
+	if DryRun {
+		output.Event("Dry run, nothing will be pushed to the remote")
+	} else {
+		var err error
+		if api, err = billing.NewAPI(); err != nil {
+			return err
+		}
+
+		if err := api.Validate(); err != nil {
+			return err
+		}
 	}
 
-	if err := api.Validate(); err != nil {
+	dirty, err := git.IsDirty()
+	if err != nil {
 		return err
 	}
-
-	if git.IsDirty() {
-		print(git.Status())
+	if dirty {
+		if status, err := git.Status(); err == nil {
+			print(status)
+		}
 		return errors.New("git status must be clean to run deps ci")
 	}
 
-	repo := pullrequest.NewRepo()
-	if repo == nil {
-		return errors.New("Repo not found or not supported")
+	cfg, err := config.FindOrInfer()
+	if err != nil {
+		return err
 	}
-	ciProvider := ci.NewCIProvider()
 
-	if err := repo.CheckRequirements(); err != nil {
+	if err := configureGit(cfg); err != nil {
 		return err
 	}
 
-	if autoconfigure {
-		ci.BaseAutoconfigure()
+	var repo pullrequest.RepoAdapter
+
+	if !DryRun {
+		repo = pullrequest.NewRepo()
+		if repo == nil {
+			return errors.New("Repo not found or not supported")
+		}
+
+		if err := repo.CheckRequirements(); err != nil {
+			return err
+		}
+	}
+
+	ciProvider := ci.NewCIProvider()
+
+	if autoconfigure && !DryRun {
+		if err := ci.BaseAutoconfigure(); err != nil {
+			return err
+		}
 
 		if err := ciProvider.Autoconfigure(); err != nil {
 			return err
 		}
 
-		repo.Autoconfigure()
+		if err := repo.Autoconfigure(); err != nil {
+			return err
+		}
 	}
 
 	output.Debug("Fetching all branches so we can check for existing updates")
-	git.Fetch()
+	if err := git.Fetch(); err != nil {
+		return err
+	}
 
-	startingBranch := getCurrentBranch(ciProvider)
+	startingBranch, err := getCurrentBranch(ciProvider)
+	if err != nil {
+		return err
+	}
 
-	git.Checkout(startingBranch)
+	if err := git.Checkout(startingBranch); err != nil {
+		return err
+	}
 
 	successfulUpdates := []*updateResult{}
 	failedUpdates := []*updateResult{}
@@ -69,11 +116,6 @@ func CI(autoconfigure bool, types []string) error {
 		return errors.New("You cannot run deps ci on a deps branch")
 	}
 
-	cfg, err := config.FindOrInfer()
-	if err != nil {
-		return err
-	}
-
 	allUpdates, err := collectUpdates(cfg, types)
 	if err != nil {
 		return err
@@ -90,10 +132,53 @@ func CI(autoconfigure bool, types []string) error {
 	output.Event("%d outdated updates", len(outdatedUpdates))
 	output.Event("%d existing updates", len(existingUpdates))
 
+	// branches that someone else has committed to are left alone
+	outdatedUpdates, manualUpdates, err := withoutManuallyModified(outdatedUpdates, startingBranch)
+	if err != nil {
+		return err
+	}
+	output.Event("%d manually modified updates", len(manualUpdates))
+
+	depsBranches, err := git.DepsBranches()
+	if err != nil {
+		return err
+	}
+
+	staleBranches := []string{}
+	if len(types) > 0 {
+		output.Debug("Not looking for stale updates because only some types were collected")
+	} else if *cfg.CloseStaleUpdates {
+		// only branches on the remote can have a pull request to close
+		remoteBranches, err := git.RemoteDepsBranches()
+		if err != nil {
+			return err
+		}
+		staleBranches, err = withoutManuallyModifiedBranches(findStaleBranches(allUpdates, remoteBranches), startingBranch)
+		if err != nil {
+			return err
+		}
+		output.Event("%d stale updates", len(staleBranches))
+	}
+
+	// stale branches are about to be closed, so don't count them
+	newUpdates, deferredUpdates, err := limitNewUpdates(newUpdates, cfg, openUpdateCount(depsBranches, staleBranches), time.Now())
+	if err != nil {
+		return err
+	}
+
+	r.addUpdates(existingUpdates, categoryExisting, statusSkipped)
+	r.addDeferred(deferredUpdates)
+	r.addManuallyModified(manualUpdates)
+
 	// TODO this is also because collectors may have done some crap and not cleaned up
-	if git.IsDirty() {
+	if dirty, err := git.IsDirty(); err != nil {
+		return err
+	} else if dirty {
 		output.Event("Temporarily saving your uncommitted changes in a git stash")
-		stashed := git.Stash(fmt.Sprintf("Deps save before update"))
+		stashed, err := git.Stash(fmt.Sprintf("Deps save before update"))
+		if err != nil {
+			return err
+		}
 
 		// Stash pop needs to happen last (so be added first)
 		defer func() {
@@ -106,44 +191,81 @@ func CI(autoconfigure bool, types []string) error {
 		}()
 	}
 
+	if DryRun {
+		for _, ur := range printDryRun(newUpdates, outdatedUpdates, startingBranch) {
+			if ur.err != nil {
+				failedUpdates = append(failedUpdates, ur)
+			}
+			r.addResults(ur)
+		}
+
+		if len(deferredUpdates) > 0 {
+			fmt.Println()
+			output.Event("%d updates would be deferred until a later run", len(deferredUpdates))
+			for _, du := range deferredUpdates {
+				output.Event("- [%s] %s (%s)", du.update.id, du.update.title, du.reason)
+			}
+		}
+
+		if len(manualUpdates) > 0 {
+			fmt.Println()
+			output.Event("%d manually modified updates would be labeled and left alone", len(manualUpdates))
+			for _, update := range manualUpdates.sorted() {
+				output.Event("- [%s] %s (%s)", update.id, update.title, update.branch)
+			}
+		}
+
+		if len(staleBranches) > 0 {
+			fmt.Println()
+			output.Event("%d stale updates would be closed", len(staleBranches))
+			for _, branch := range staleBranches {
+				output.Event("- %s", branch)
+			}
+		}
+
+		if len(failedUpdates) > 0 {
+			return fmt.Errorf("%d errors", len(failedUpdates))
+		}
+
+		return nil
+	}
+
+	closeStaleUpdates(repo, staleBranches)
+	labelManuallyModified(repo, manualUpdates)
+
 	output.Event("Performing %d new updates on %s", len(newUpdates), startingBranch)
 
-	for _, update := range newUpdates {
-		output.Event("Running update: %s", update.title)
-		if err := runUpdate(update, startingBranch, update.branch, false); err != nil {
-			failedUpdates = append(failedUpdates, &updateResult{
-				update: update,
-				err:    err,
-			})
-			output.Error("Update failed: %v", err)
-		} else {
-			successfulUpdates = append(successfulUpdates, &updateResult{
-				update: update,
-				err:    err,
-			})
-			output.Success("Update succeeded: %v", update.title)
-		}
-	}
-
-	for _, update := range outdatedUpdates {
-		output.Event("Updating outdated update: %s", update.title)
-		// TODO if update.branch already exists, maybe base could be
-		// determined from what it originally branched off of?
-		if err := runUpdate(update, startingBranch, update.branch, true); err != nil {
-			failedUpdates = append(failedUpdates, &updateResult{
-				update: update,
-				err:    err,
-			})
-			output.Error("Update failed: %v", err)
-		} else {
-			successfulUpdates = append(successfulUpdates, &updateResult{
-				update: update,
-				err:    err,
-			})
-			output.Success("Update succeeded: %v", update.title)
+	if Worktrees {
+		for _, ur := range performUpdatesInWorktrees(newUpdates, outdatedUpdates, startingBranch, cfg.OutdatedBranches) {
+			if ur.err != nil {
+				failedUpdates = append(failedUpdates, ur)
+			} else {
+				successfulUpdates = append(successfulUpdates, ur)
+			}
+		}
+	} else {
+		for _, update := range newUpdates {
+			output.Event("Running update: %s", update.title)
+			if ur := performUpdate(update, startingBranch, categoryNew, cfg.OutdatedBranches); ur.err != nil {
+				failedUpdates = append(failedUpdates, ur)
+			} else {
+				successfulUpdates = append(successfulUpdates, ur)
+			}
+		}
+
+		for _, update := range outdatedUpdates {
+			output.Event("Updating outdated update: %s", update.title)
+			if ur := performUpdate(update, startingBranch, categoryOutdated, cfg.OutdatedBranches); ur.err != nil {
+				failedUpdates = append(failedUpdates, ur)
+			} else {
+				successfulUpdates = append(successfulUpdates, ur)
+			}
 		}
 	}
 
+	r.addResults(successfulUpdates...)
+	r.addResults(failedUpdates...)
+
 	if len(successfulUpdates) > 0 {
 		output.Success("%d updates made successfully!", len(successfulUpdates))
 		for _, ue := range successfulUpdates {
@@ -151,6 +273,20 @@ func CI(autoconfigure bool, types []string) error {
 		}
 	}
 
+	if len(deferredUpdates) > 0 {
+		output.Event("%d updates were deferred until a later run", len(deferredUpdates))
+		for _, du := range deferredUpdates {
+			output.Event("- [%s] %s (%s)", du.update.id, du.update.title, du.reason)
+		}
+	}
+
+	if len(manualUpdates) > 0 {
+		output.Event("%d manually modified updates were left alone", len(manualUpdates))
+		for _, update := range manualUpdates.sorted() {
+			output.Event("- [%s] %s (%s)", update.id, update.title, update.branch)
+		}
+	}
+
 	if len(failedUpdates) > 0 {
 		output.Error("There were %d errors making the updates", len(failedUpdates))
 		for _, ue := range failedUpdates {
@@ -171,8 +307,11 @@ func CI(autoconfigure bool, types []string) error {
 	return nil
 }
 
-func getCurrentBranch(ci ci.CIProvider) string {
-	branch := git.CurrentRef()
+func getCurrentBranch(ci ci.CIProvider) (string, error) {
+	branch, err := git.CurrentRef()
+	if err != nil {
+		return "", err
+	}
 
 	// CI environments may be checking out a specific ref,
 	// so use the variables they provide to see if we get a different branch name
@@ -182,57 +321,133 @@ func getCurrentBranch(ci ci.CIProvider) string {
 	}
 
 	if branch == "" {
-		panic(errors.New("Unable to determine base branch"))
+		return "", errors.New("Unable to determine base branch")
 	}
 
 	if branch == "HEAD" {
-		panic(errors.New("Unable to determine base branch, only got HEAD"))
+		return "", errors.New("Unable to determine base branch, only got HEAD")
 	}
 
-	return branch
+	return branch, nil
 }
 
-func runUpdate(update *Update, base, head string, existingUpdate bool) error {
-	if existingUpdate {
-		// go straight to it
-		git.Checkout(head)
+func performUpdate(update *Update, base, category, outdatedBranches string) *updateResult {
+	return recordUpdate(update, category, nil, func() (string, error) {
+		// TODO if update.branch already exists, maybe base could be
+		// determined from what it originally branched off of?
+		return runUpdate(update, base, update.branch, category == categoryOutdated, outdatedBranches)
+	})
+}
+
+// recordUpdate times an update and logs the result
+func recordUpdate(update *Update, category string, logger *output.Logger, run func() (string, error)) *updateResult {
+	ur := &updateResult{
+		update:    update,
+		category:  category,
+		startedAt: time.Now(),
+	}
+
+	ur.pullrequestURL, ur.err = run()
+	ur.duration = time.Since(ur.startedAt)
+
+	if ur.err != nil {
+		ur.status = statusFailure
+		logger.Error("Update failed: %v", ur.err)
 	} else {
-		// create a branch for it
-		git.Checkout(base)
-		git.Branch(head)
+		ur.status = statusSuccess
+		logger.Success("Update succeeded: %v", update.title)
 	}
 
+	return ur
+}
+
+func runUpdate(update *Update, base, head string, existingUpdate bool, outdatedBranches string) (url string, err error) {
+	// a recreated branch starts over from the base, like a new update
+	recreate := existingUpdate && outdatedBranches == config.OutdatedBranchesRecreate
+	rebase := existingUpdate && outdatedBranches == config.OutdatedBranchesRebase
+
 	defer func() {
 		// There should only be uncommitted changes if we're bailing early
-		git.ResetAndClean()
-		git.CheckoutLast()
+		cleanupErr := git.ResetAndClean()
+		if cleanupErr == nil {
+			// not CheckoutLast, since a rebase can change what "last" is
+			cleanupErr = git.Checkout(base)
+		}
+		if cleanupErr != nil && err == nil {
+			err = cleanupErr
+		}
 	}()
 
-	outputDeps, err := update.runner.Act(update.dependencies)
+	if recreate {
+		if err := git.RecreateBranch(head, base); err != nil {
+			return "", err
+		}
+	} else if existingUpdate {
+		// go straight to it
+		if err := git.Checkout(head); err != nil {
+			return "", err
+		}
+		if rebase {
+			if err := git.Rebase(base); err != nil {
+				return "", fmt.Errorf("Unable to rebase %s onto %s: %v", head, base, err)
+			}
+		}
+	} else {
+		// create a branch for it
+		if err := git.Checkout(base); err != nil {
+			return "", err
+		}
+		if err := git.Branch(head); err != nil {
+			return "", err
+		}
+	}
+
+	outputDeps, err := update.act()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	pr, err := pullrequest.NewPullrequest(base, head, outputDeps, update.dependencyConfig)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if !git.IsDirty() {
-		if existingUpdate {
+	dirty, err := git.IsDirty()
+	if err != nil {
+		return "", err
+	}
+	if !dirty {
+		if existingUpdate && !recreate {
 			output.Event("No new changes to commit")
-			return nil
+			if rebase {
+				// the rebased branch still needs to be pushed
+				return "", git.ForcePushBranch(head)
+			}
+			return "", nil
 		}
 
-		return errors.New("Update didn't generate any changes to commit")
+		return "", errors.New("Update didn't generate any changes to commit")
 	}
 
-	git.Add()
-	git.Commit(schemaext.TitleForDeps(outputDeps))
-	// TODO try adding more lines for dependency breakdown,
-	// especially on lockfiles
+	if err := git.Add(); err != nil {
+		return "", err
+	}
+	message, err := update.commitMessage(outputDeps)
+	if err != nil {
+		return "", err
+	}
+	if err := git.Commit(message); err != nil {
+		return "", err
+	}
 
-	git.PushBranch(head)
+	if recreate || rebase {
+		err = git.ForcePushBranch(head)
+	} else {
+		err = git.PushBranch(head)
+	}
+	if err != nil {
+		return "", err
+	}
 
 	// TODO hooks or what do you do otherwise?
 
@@ -241,9 +456,11 @@ func runUpdate(update *Update, base, head string, existingUpdate bool) error {
 		time.Sleep(2 * time.Second)
 
 		if err := pr.CreateOrUpdate(); err != nil {
-			return err
+			return "", err
 		}
+
+		return pr.GetURL(), nil
 	}
 
-	return nil
+	return "", nil
 }
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/internal/schemaext"
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+// DryRun plans the updates without committing, pushing,
+// opening pull requests or using the billing API
+var DryRun = false
+
+// DryRunAct also makes the updates in a temporary worktree to show the changes
+var DryRunAct = false
+
+func printDryRun(newUpdates, outdatedUpdates Updates, base string) []*updateResult {
+	results := []*updateResult{}
+
+	for _, group := range []struct {
+		action   string
+		category string
+		updates  Updates
+	}{
+		{"create", categoryNew, newUpdates},
+		{"update", categoryOutdated, outdatedUpdates},
+	} {
+		for _, update := range group.updates.sorted() {
+			fmt.Println()
+			output.Event("[%s] Would %s %s from %s", update.id, group.action, update.branch, base)
+
+			deps := update.dependencies
+			diff := ""
+
+			if DryRunAct {
+				outputDeps, d, err := dryRunAct(update, base)
+				if err != nil {
+					output.Error("Update failed: %v", err)
+					results = append(results, &updateResult{
+						update:   update,
+						err:      err,
+						category: group.category,
+						status:   statusFailure,
+					})
+					continue
+				}
+				deps = outputDeps
+				diff = d
+			}
+
+			fmt.Printf("Title: %s\n\n%s\n", schemaext.TitleForDeps(deps, update.dependencyConfig.LockfileUpdates.Split), schemaext.DescriptionForDeps(deps))
+			if diff != "" {
+				fmt.Printf("\n%s\n", diff)
+			}
+
+			results = append(results, &updateResult{
+				update:   update,
+				category: group.category,
+				status:   statusPlanned,
+			})
+		}
+	}
+
+	return results
+}
+
+// dryRunAct runs the update in a throwaway worktree from base
+// and returns the output from the components and the resulting diff
+func dryRunAct(update *Update, base string) (*schema.Dependencies, string, error) {
+	worktree, err := git.AddWorktree(base)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err := worktree.Remove(); err != nil {
+			output.Error("Error removing worktree %s: %v", worktree.Path, err)
+		}
+	}()
+
+	outputDeps, err := update.actIn(worktree.Path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	diff, err := worktree.Diff()
+	if err != nil {
+		return nil, "", err
+	}
+	if diff == "" {
+		return nil, "", fmt.Errorf("Update didn't generate any changes to commit")
+	}
+
+	return outputDeps, diff, nil
+}
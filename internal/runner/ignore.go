@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/dropseed/deps/internal/config"
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+// withoutIgnoredManifestDependencies returns a copy of the manifest
+// without the updates that are on the ignore list
+func withoutIgnoredManifestDependencies(path string, manifest *schema.Manifest, dependencyConfig *config.Dependency, now time.Time) (*schema.Manifest, error) {
+	filtered := &schema.Manifest{
+		LockfilePath: manifest.LockfilePath,
+		Current:      manifest.Current,
+		Updated: &schema.ManifestVersion{
+			Dependencies: map[string]*schema.ManifestDependency{},
+		},
+	}
+
+	for name, dep := range manifest.Updated.Dependencies {
+		ignore, err := dependencyConfig.IgnoreForManifestDependency(name, dep, now)
+		if err != nil {
+			return nil, err
+		}
+		if ignore != nil {
+			output.Event("Ignoring %s update to %s in %s: %s", name, dep.Constraint, path, ignore.Description())
+			continue
+		}
+		filtered.Updated.Dependencies[name] = dep
+	}
+
+	return filtered, nil
+}
+
+// ignoredLockfileDependencies checks the changed lockfile dependencies against
+// the ignore list, returning the names that should stay at their current versions
+func ignoredLockfileDependencies(path string, lockfile *schema.Lockfile, dependencyConfig *config.Dependency, now time.Time) (map[string]bool, error) {
+	ignored := map[string]bool{}
+	for name, dep := range lockfile.Updated.Dependencies {
+		if current, found := lockfile.Current.Dependencies[name]; found && current.Version.Name == dep.Version.Name {
+			continue
+		}
+		ignore, err := dependencyConfig.IgnoreForLockfileDependency(name, dep, now)
+		if err != nil {
+			return nil, err
+		}
+		if ignore != nil {
+			output.Event("Ignoring %s update to %s in %s: %s", name, dep.Version.Name, path, ignore.Description())
+			ignored[name] = true
+		}
+	}
+	return ignored, nil
+}
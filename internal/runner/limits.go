@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dropseed/deps/internal/config"
+)
+
+type deferredUpdate struct {
+	update *Update
+	reason string
+}
+
+// openUpdateCount is how many deps branches will still be open
+// after the stale ones are closed
+func openUpdateCount(depsBranches, staleBranches []string) int {
+	return len(depsBranches) - len(staleBranches)
+}
+
+// limitNewUpdates holds back the new updates that are outside of their
+// schedule or would go over the max_open_updates or max_new_updates_per_run
+func limitNewUpdates(newUpdates Updates, cfg *config.Config, openUpdates int, now time.Time) (Updates, []*deferredUpdate, error) {
+	allowed := Updates{}
+	deferred := []*deferredUpdate{}
+
+	remaining := -1 // unlimited
+	if cfg.MaxOpenUpdates > 0 {
+		remaining = cfg.MaxOpenUpdates - openUpdates
+		if remaining < 0 {
+			remaining = 0
+		}
+	}
+	if cfg.MaxNewUpdatesPerRun > 0 && (remaining < 0 || cfg.MaxNewUpdatesPerRun < remaining) {
+		remaining = cfg.MaxNewUpdatesPerRun
+	}
+
+	for _, update := range newUpdates.sorted() {
+		if schedule := update.dependencyConfig.Schedule; schedule != nil {
+			inSchedule, err := schedule.IsAllowed(now)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !inSchedule {
+				deferred = append(deferred, &deferredUpdate{
+					update: update,
+					reason: fmt.Sprintf("outside of schedule %s", schedule),
+				})
+				continue
+			}
+		}
+
+		if remaining == 0 {
+			deferred = append(deferred, &deferredUpdate{
+				update: update,
+				reason: "update limit reached",
+			})
+			continue
+		}
+
+		allowed.addUpdate(update)
+		if remaining > 0 {
+			remaining--
+		}
+	}
+
+	return allowed, deferred, nil
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dropseed/deps/internal/component"
+	"github.com/dropseed/deps/internal/config"
+)
+
+func testNewUpdates(count int, schedule *config.Schedule) Updates {
+	updates := Updates{}
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("update-%d", i)
+		updates[id] = &Update{
+			id:               id,
+			dependencyConfig: &config.Dependency{Schedule: schedule},
+			runner:           &component.Runner{Index: i},
+		}
+	}
+	return updates
+}
+
+func TestLimitNewUpdates(t *testing.T) {
+	// a Tuesday afternoon
+	now := time.Date(2020, 6, 2, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		updates     Updates
+		maxOpen     int
+		maxNew      int
+		openUpdates int
+		allowed     int
+		reason      string
+	}{
+		{
+			name:    "unlimited",
+			updates: testNewUpdates(3, nil),
+			allowed: 3,
+		},
+		{
+			name:        "max_open_updates",
+			updates:     testNewUpdates(3, nil),
+			maxOpen:     3,
+			openUpdates: 1,
+			allowed:     2,
+			reason:      "update limit reached",
+		},
+		{
+			name:        "already over max_open_updates",
+			updates:     testNewUpdates(3, nil),
+			maxOpen:     3,
+			openUpdates: 5,
+			allowed:     0,
+			reason:      "update limit reached",
+		},
+		{
+			name:    "max_new_updates_per_run",
+			updates: testNewUpdates(3, nil),
+			maxNew:  1,
+			allowed: 1,
+			reason:  "update limit reached",
+		},
+		{
+			name:        "lower of the two limits",
+			updates:     testNewUpdates(3, nil),
+			maxOpen:     10,
+			maxNew:      2,
+			openUpdates: 0,
+			allowed:     2,
+			reason:      "update limit reached",
+		},
+		{
+			name:        "stale branches aren't counted",
+			updates:     testNewUpdates(3, nil),
+			maxOpen:     3,
+			openUpdates: openUpdateCount([]string{"deps/a", "deps/b", "deps/c", "deps/d"}, []string{"deps/c", "deps/d"}),
+			allowed:     1,
+			reason:      "update limit reached",
+		},
+		{
+			name:    "outside of schedule",
+			updates: testNewUpdates(2, &config.Schedule{Days: []string{"mon"}, Timezone: "UTC"}),
+			maxNew:  1,
+			allowed: 0,
+			reason:  "outside of schedule",
+		},
+		{
+			name:    "inside schedule",
+			updates: testNewUpdates(2, &config.Schedule{Days: []string{"tue"}, Hours: "9-17", Timezone: "UTC"}),
+			allowed: 2,
+		},
+	}
+
+	for _, test := range tests {
+		cfg := &config.Config{MaxOpenUpdates: test.maxOpen, MaxNewUpdatesPerRun: test.maxNew}
+		allowed, deferred, err := limitNewUpdates(test.updates, cfg, test.openUpdates, now)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(allowed) != test.allowed {
+			t.Errorf("%s: expected %d allowed, got %d", test.name, test.allowed, len(allowed))
+		}
+		if len(allowed)+len(deferred) != len(test.updates) {
+			t.Errorf("%s: expected every update to be allowed or deferred", test.name)
+		}
+		for _, du := range deferred {
+			if !strings.HasPrefix(du.reason, test.reason) || test.reason == "" {
+				t.Errorf("%s: unexpected reason %q", test.name, du.reason)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dropseed/deps/internal/config"
 	"github.com/dropseed/deps/internal/git"
@@ -11,15 +12,33 @@ import (
 	"github.com/manifoldco/promptui"
 )
 
-// Local runs a full interactive update process
-func Local() error {
-	if git.HasStagedChanges() {
+// Local runs a full update process, interactively
+// unless a selection of updates is given
+func Local(selection *Selection) error {
+	r := newReport("upgrade")
+	return r.finish(runLocal(selection, r))
+}
+
+func runLocal(selection *Selection, r *report) error {
+	if !selection.IsEmpty() {
+		if err := selection.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if staged, err := git.HasStagedChanges(); err != nil {
+		return err
+	} else if staged {
 		return errors.New("You can't have staged changes while running this command. Please commit or unstage them.")
 	}
 
-	if git.IsDirty() {
+	if dirty, err := git.IsDirty(); err != nil {
+		return err
+	} else if dirty {
 		output.Warning("You have uncommitted changes! We are going to stage them so that we can tell the difference between changes. They will be unstaged when this command exits.\n")
-		git.Add()
+		if err := git.Add(); err != nil {
+			return err
+		}
 	}
 
 	cfg, err := config.FindOrInfer()
@@ -27,30 +46,93 @@ func Local() error {
 		return err
 	}
 
-	allUpdates, err := collectUpdates(cfg, []string{})
+	if err := configureGit(cfg); err != nil {
+		return err
+	}
+
+	types := []string{}
+	if selection != nil {
+		types = selection.Types
+	}
+
+	allUpdates, err := collectUpdates(cfg, types)
 	if err != nil {
 		return err
 	}
 
-	if git.HasStagedChanges() || git.IsDirty() {
+	staged, err := git.HasStagedChanges()
+	if err != nil {
+		return err
+	}
+	dirty, err := git.IsDirty()
+	if err != nil {
+		return err
+	}
+	if staged || dirty {
 		output.Debug("Restoring the state of your repo before updates were collected")
-		git.Checkout(".")
-		git.Unstage()
+		if err := git.Checkout("."); err != nil {
+			return err
+		}
+		if err := git.Unstage(); err != nil {
+			return err
+		}
 	}
 
-	newUpdates, _, _, err := organizeUpdates(allUpdates)
+	newUpdates, outdatedUpdates, existingUpdates, err := organizeUpdates(allUpdates)
 	if err != nil {
 		return err
 	}
 
-	if err := newUpdates.prompt(); err != nil {
+	r.addUpdates(outdatedUpdates, categoryOutdated, statusSkipped)
+	r.addUpdates(existingUpdates, categoryExisting, statusSkipped)
+
+	if selection.IsEmpty() {
+		err = newUpdates.prompt(r)
+	} else {
+		err = newUpdates.actOnSelected(selection, r)
+	}
+
+	for _, update := range newUpdates.sorted() {
+		if !update.completed {
+			r.addUpdates(Updates{update.id: update}, categoryNew, statusSkipped)
+		}
+	}
+
+	if err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (updates Updates) prompt() error {
+// actOnSelected makes every selected update, continuing after failures
+func (updates Updates) actOnSelected(selection *Selection, r *report) error {
+	failed := 0
+
+	for _, update := range updates.sorted() {
+		if !selection.matches(update) {
+			continue
+		}
+
+		output.Event("Running update: %s", update.title)
+		if ur := update.actLocally(); ur.err != nil {
+			output.Error("Update failed: %v", ur.err)
+			r.addResults(ur)
+			failed++
+		} else {
+			output.Success("Update succeeded: %v", update.title)
+			r.addResults(ur)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d errors", failed)
+	}
+
+	return nil
+}
+
+func (updates Updates) prompt(r *report) error {
 	for {
 		refs := map[int]string{}
 		items := []string{}
@@ -87,12 +169,28 @@ func (updates Updates) prompt() error {
 			break
 		}
 
-		update := updates[refs[i]]
-		if _, err := update.runner.Act(update.dependencies); err != nil {
-			return err
+		ur := updates[refs[i]].actLocally()
+		r.addResults(ur)
+		if ur.err != nil {
+			return ur.err
 		}
-		update.completed = true
 	}
 
 	return nil
 }
+
+func (update *Update) actLocally() *updateResult {
+	ur := &updateResult{
+		update:    update,
+		category:  categoryNew,
+		status:    statusSuccess,
+		startedAt: time.Now(),
+	}
+	_, ur.err = update.act()
+	ur.duration = time.Since(ur.startedAt)
+	update.completed = true
+	if ur.err != nil {
+		ur.status = statusFailure
+	}
+	return ur
+}
@@ -2,22 +2,46 @@ package runner
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/dropseed/deps/internal/component"
 	"github.com/dropseed/deps/internal/config"
+	"github.com/dropseed/deps/internal/git"
 	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/pkg/schema"
 )
 
+// configureGit applies the git settings from the config
+// and checks that the git backend can do what they need
+func configureGit(cfg *config.Config) error {
+	if cfg.OutdatedBranches == config.OutdatedBranchesRebase && !git.CanRebase() {
+		return fmt.Errorf("outdated_branches \"%s\" needs the git executable, set DEPS_GIT_BACKEND=exec or use \"%s\"", config.OutdatedBranchesRebase, config.OutdatedBranchesRecreate)
+	}
+	return git.Configure(cfg.Git.Remote, cfg.Git.BranchPrefix, cfg.Git.BranchSeparator)
+}
+
 func organizeUpdates(updates Updates) (Updates, Updates, Updates, error) {
 	newUpdates := Updates{}      // PRs for these
 	outdatedUpdates := Updates{} // lockfile update on these?
 	existingUpdates := Updates{}
 
 	for _, update := range updates {
-		if update.exists() {
+		exists, err := update.exists()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if exists {
 			existingUpdates.addUpdate(update)
-		} else if outdated := update.outdatedBranch(); outdated != "" {
+			continue
+		}
+
+		outdated, err := update.outdatedBranch()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if outdated != "" {
 			update.branch = outdated // change the branch to the existing match
 			outdatedUpdates.addUpdate(update)
 		} else {
@@ -46,6 +70,32 @@ func organizeUpdates(updates Updates) (Updates, Updates, Updates, error) {
 	return newUpdates, outdatedUpdates, existingUpdates, nil
 }
 
+// Jobs is the number of dependency entries to install and collect at once
+var Jobs = 1
+
+// collection is the result of installing and collecting a single dependency entry
+type collection struct {
+	dependencyConfig *config.Dependency
+	runner           *component.Runner
+	dependencies     *schema.Dependencies
+	err              error
+}
+
+// installation makes sure a component is only installed once,
+// even if multiple entries use it at the same time
+type installation struct {
+	once sync.Once
+	err  error
+}
+
+// installKey is the same for entries that can share an install,
+// which have to use the same component with the same settings and env
+func installKey(runner *component.Runner) string {
+	env := append([]string{}, runner.Env...)
+	sort.Strings(env)
+	return runner.Path + "\x00" + strings.Join(env, "\x00")
+}
+
 func collectUpdates(cfg *config.Config, types []string) (Updates, error) {
 	if len(types) > 0 {
 		output.Event("Only collecting types: %s", strings.Join(types, ", "))
@@ -55,8 +105,11 @@ func collectUpdates(cfg *config.Config, types []string) (Updates, error) {
 		typesMap[t] = true
 	}
 
-	updates := Updates{}
+	collections := []*collection{}
+	installations := map[string]*installation{}
 
+	// Runners are created one at a time because
+	// remote components are cloned/pulled in place
 	for index, dependencyConfig := range cfg.Dependencies {
 
 		if _, ok := typesMap[dependencyConfig.Type]; len(typesMap) > 0 && !ok {
@@ -74,28 +127,86 @@ func collectUpdates(cfg *config.Config, types []string) (Updates, error) {
 		runner.Index = index
 		runner.Env = env
 
-		if err := runner.Install(); err != nil {
-			return nil, err
+		if _, found := installations[installKey(runner)]; !found {
+			installations[installKey(runner)] = &installation{}
 		}
 
-		dependencies, err := runner.Collect(dependencyConfig.Path)
-		if err != nil {
-			return nil, err
+		collections = append(collections, &collection{
+			dependencyConfig: dependencyConfig,
+			runner:           runner,
+		})
+	}
+
+	jobs := Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs > 1 && len(collections) > 1 {
+		output.Event("Collecting %d dependency entries, %d at a time", len(collections), jobs)
+	}
+
+	queue := make(chan *collection)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range queue {
+				install := installations[installKey(c.runner)]
+				install.once.Do(func() {
+					install.err = c.runner.Install()
+				})
+				if install.err != nil {
+					c.err = install.err
+					continue
+				}
+				c.dependencies, c.err = c.runner.Collect(c.dependencyConfig.Path)
+			}
+		}()
+	}
+
+	for _, c := range collections {
+		if jobs > 1 {
+			// keep the output for each entry together
+			c.runner.Logger = output.NewLogger()
 		}
+		queue <- c
+	}
+	close(queue)
+	wg.Wait()
+
+	updates := Updates{}
+
+	// Results are used in the order of the config,
+	// regardless of which finished first
+	for _, c := range collections {
+		c.runner.Logger.Flush()
+		c.runner.Logger = nil
 
-		depUpdates, err := newUpdatesFromDependencies(dependencies, dependencyConfig)
+		if c.err != nil {
+			return nil, c.err
+		}
+
+		depUpdates, err := newUpdatesFromDependencies(c.dependencies, c.dependencyConfig)
 		if err != nil {
 			return nil, err
 		}
 
 		if len(depUpdates) > 0 {
 			for _, update := range depUpdates {
-				// Store this for use later
-				update.runner = runner
+				// Store this for use later (before adding, so
+				// named groups from other entries keep their runners)
+				update.runner = c.runner
 				updates.addUpdate(update)
 			}
 		}
 	}
 
+	if cfg.Grouping == config.GroupingAll && len(updates) > 1 {
+		output.Event("Combining %d updates into 1", len(updates))
+		updates = updates.combineAll()
+	}
+
 	return updates, nil
 }
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dropseed/deps/internal/config"
+)
+
+func TestCollectInstallsPerSettings(t *testing.T) {
+	componentPath := t.TempDir()
+	componentConfig := `install: echo "$DEPS_SETTING_NAME" >> install.log
+collect: collect() { echo '{}' > "$2"; }; collect
+`
+	if err := ioutil.WriteFile(filepath.Join(componentPath, "deps_component.yml"), []byte(componentConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Version: config.Version,
+		Dependencies: []*config.Dependency{
+			{Type: componentPath, Path: ".", Settings: config.Settings{"name": "first"}},
+			{Type: componentPath, Path: ".", Settings: config.Settings{"name": "second"}},
+			{Type: componentPath, Path: ".", Settings: config.Settings{"name": "second"}},
+		},
+	}
+	cfg.Compile()
+
+	for _, jobs := range []int{1, 2} {
+		Jobs = jobs
+		if err := ioutil.WriteFile(filepath.Join(componentPath, "install.log"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := collectUpdates(cfg, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		log, err := ioutil.ReadFile(filepath.Join(componentPath, "install.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		installs := strings.Fields(string(log))
+		sort.Strings(installs)
+		if strings.Join(installs, " ") != "first second" {
+			t.Errorf("expected one install for each set of settings with %d jobs, got %v", jobs, installs)
+		}
+	}
+	Jobs = 1
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"strings"
+
+	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/internal/pullrequest"
+)
+
+const manuallyModifiedLabel = "deps: manually modified"
+const manuallyModifiedReason = "manually modified"
+
+// isManuallyModified checks if anyone besides the git user that deps commits
+// with has committed to the branch, matching authors by email
+func isManuallyModified(base, branch string) (bool, error) {
+	botEmail, err := git.UserEmail()
+	if err != nil {
+		return false, err
+	}
+	if botEmail == "" {
+		output.Debug("No git user.email to compare the commits on %s to", branch)
+		return false, nil
+	}
+
+	authors, err := git.BranchAuthors(base, branch)
+	if err != nil {
+		return false, err
+	}
+
+	for _, author := range authors {
+		if !strings.EqualFold(author.Email, botEmail) {
+			output.Debug("%s has a commit by %s <%s>", branch, author.Name, author.Email)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// withoutManuallyModified separates the updates whose branches have
+// commits from someone else, which deps should leave alone
+func withoutManuallyModified(updates Updates, base string) (Updates, Updates, error) {
+	remaining := Updates{}
+	manual := Updates{}
+
+	for id, update := range updates {
+		modified, err := isManuallyModified(base, update.branch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if modified {
+			manual[id] = update
+		} else {
+			remaining[id] = update
+		}
+	}
+
+	return remaining, manual, nil
+}
+
+// withoutManuallyModifiedBranches is the same as withoutManuallyModified,
+// for branches that don't have an update
+func withoutManuallyModifiedBranches(branches []string, base string) ([]string, error) {
+	remaining := []string{}
+
+	for _, branch := range branches {
+		modified, err := isManuallyModified(base, branch)
+		if err != nil {
+			return nil, err
+		}
+		if modified {
+			output.Event("Leaving %s alone because it was manually modified", branch)
+		} else {
+			remaining = append(remaining, branch)
+		}
+	}
+
+	return remaining, nil
+}
+
+// labelManuallyModified labels the pull requests for the updates,
+// continuing after failures
+func labelManuallyModified(repo pullrequest.RepoAdapter, updates Updates) {
+	for _, update := range updates.sorted() {
+		labeled, err := repo.AddLabel(update.branch, manuallyModifiedLabel)
+		for _, url := range labeled {
+			output.Event("Labeled %s as %s", url, manuallyModifiedLabel)
+		}
+		if err != nil {
+			output.Error("Unable to label the pull request for %s: %v", update.branch, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/dropseed/deps/internal/git"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func commitAs(t *testing.T, b git.Backend, fs billy.Filesystem, user, path string) {
+	t.Helper()
+	if err := b.SetUser(user, user+"@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteFile(fs, path, []byte(user+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Commit("Commit by " + user); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithoutManuallyModified(t *testing.T) {
+	fs := memfs.New()
+	repo, err := gogit.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := git.NewInProcessBackend(repo)
+	git.SetBackend(b)
+
+	commitAs(t, b, fs, "deps", "deps.yml")
+
+	for branch, users := range map[string][]string{
+		"deps/update-bot":    {"deps"},
+		"deps/update-manual": {"deps", "someone"},
+	} {
+		if err := b.Branch(branch); err != nil {
+			t.Fatal(err)
+		}
+		for _, user := range users {
+			commitAs(t, b, fs, user, user+".txt")
+		}
+		if err := b.Checkout("master"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updates := Updates{
+		"bot":    &Update{id: "bot", branch: "deps/update-bot"},
+		"manual": &Update{id: "manual", branch: "deps/update-manual"},
+	}
+
+	// deps commits as whoever is configured
+	if err := b.SetUser("deps", "deps@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	remaining, manual, err := withoutManuallyModified(updates, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 1 || remaining["bot"] == nil {
+		t.Errorf("unexpected remaining updates %v", remaining)
+	}
+	if len(manual) != 1 || manual["manual"] == nil {
+		t.Errorf("unexpected manually modified updates %v", manual)
+	}
+
+	// without an identity to compare to, nothing is left alone
+	if err := b.SetUser("", ""); err != nil {
+		t.Fatal(err)
+	}
+	remaining, manual, err = withoutManuallyModified(updates, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 || len(manual) != 0 {
+		t.Errorf("unexpected split %v %v", remaining, manual)
+	}
+}
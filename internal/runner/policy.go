@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dropseed/deps/pkg/versioning"
+)
+
+// Policy decides whether the pending updates found by deps check are acceptable
+type Policy struct {
+	// MaxPending is the most updates that can be pending (-1 is unlimited)
+	MaxPending int
+	// MaxAge is the number of days an update of each type can go unmerged
+	MaxAge map[versioning.UpdateType]int
+}
+
+// ParsePolicy creates a Policy from a max pending count
+// and max ages like "major=90"
+func ParsePolicy(maxPending int, maxAges []string) (*Policy, error) {
+	policy := &Policy{
+		MaxPending: maxPending,
+		MaxAge:     map[versioning.UpdateType]int{},
+	}
+
+	for _, s := range maxAges {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 || !versioning.IsValid(parts[0]) {
+			return nil, fmt.Errorf("Invalid max age \"%s\", should be an update type and days like \"major=90\"", s)
+		}
+		days, err := strconv.Atoi(parts[1])
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("Invalid max age \"%s\", days should be 0 or more", s)
+		}
+		policy.MaxAge[versioning.UpdateType(parts[0])] = days
+	}
+
+	return policy, nil
+}
+
+// violations lists the ways the pending updates break the policy
+func (policy *Policy) violations(updates Updates, rows []*checkRow, now time.Time) []string {
+	violations := []string{}
+
+	if policy.MaxPending >= 0 && len(updates) > policy.MaxPending {
+		violations = append(violations, fmt.Sprintf("%d updates are pending (max %d)", len(updates), policy.MaxPending))
+	}
+
+	for _, row := range rows {
+		days, found := policy.MaxAge[row.UpdateType]
+		if !found || row.ReleasedAt == nil {
+			// releases without a date can't be checked
+			continue
+		}
+		age := int(now.Sub(*row.ReleasedAt).Hours() / 24)
+		if age > days {
+			violations = append(violations, fmt.Sprintf("%s %s update to %s in %s was released %d days ago (max %d)", row.Name, row.UpdateType, row.Available, row.Path, age, days))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// ReportPath is where a JSON report of the run is written (if set)
+var ReportPath = ""
+
+// Update categories from organizeUpdates
+const (
+	categoryNew      = "new"
+	categoryOutdated = "outdated"
+	categoryExisting = "existing"
+)
+
+// Update statuses in the report
+const (
+	statusSuccess  = "success"
+	statusFailure  = "failure"
+	statusSkipped  = "skipped"
+	statusDeferred = "deferred"
+	statusPlanned  = "planned"
+)
+
+type report struct {
+	Command    string          `json:"command"`
+	DryRun     bool            `json:"dry_run,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Error      string          `json:"error,omitempty"`
+	Updates    []*reportUpdate `json:"updates"`
+}
+
+type reportUpdate struct {
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Branch         string     `json:"branch"`
+	Category       string     `json:"category"`
+	Status         string     `json:"status"`
+	Error          string     `json:"error,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	PullrequestURL string     `json:"pullrequest_url,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	Duration       float64    `json:"duration_seconds,omitempty"`
+}
+
+func newReport(command string) *report {
+	return &report{
+		Command:   command,
+		DryRun:    DryRun,
+		StartedAt: time.Now(),
+		Updates:   []*reportUpdate{},
+	}
+}
+
+func (r *report) addResults(results ...*updateResult) {
+	for _, result := range results {
+		ru := &reportUpdate{
+			ID:             result.update.id,
+			Title:          result.update.title,
+			Branch:         result.update.branch,
+			Category:       result.category,
+			Status:         result.status,
+			Reason:         result.reason,
+			PullrequestURL: result.pullrequestURL,
+		}
+		if result.err != nil {
+			ru.Error = result.err.Error()
+		}
+		if !result.startedAt.IsZero() {
+			startedAt := result.startedAt
+			ru.StartedAt = &startedAt
+			ru.Duration = result.duration.Seconds()
+		}
+		r.Updates = append(r.Updates, ru)
+	}
+}
+
+func (r *report) addUpdates(updates Updates, category, status string) {
+	for _, update := range updates.sorted() {
+		r.addResults(&updateResult{
+			update:   update,
+			category: category,
+			status:   status,
+		})
+	}
+}
+
+func (r *report) addDeferred(deferred []*deferredUpdate) {
+	for _, du := range deferred {
+		r.addResults(&updateResult{
+			update:   du.update,
+			category: categoryNew,
+			status:   statusDeferred,
+			reason:   du.reason,
+		})
+	}
+}
+
+func (r *report) addManuallyModified(updates Updates) {
+	for _, update := range updates.sorted() {
+		r.addResults(&updateResult{
+			update:   update,
+			category: categoryOutdated,
+			status:   statusSkipped,
+			reason:   manuallyModifiedReason,
+		})
+	}
+}
+
+// finish writes the report (if there is a ReportPath)
+// and passes along the error from the run
+func (r *report) finish(runErr error) error {
+	if ReportPath == "" {
+		return runErr
+	}
+
+	r.FinishedAt = time.Now()
+	if runErr != nil {
+		r.Error = runErr.Error()
+	}
+
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(ReportPath, out, 0644); err != nil {
+		if runErr != nil {
+			return runErr
+		}
+		return err
+	}
+
+	return runErr
+}
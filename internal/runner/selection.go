@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Selection picks updates to make without prompting. Each kind of
+// filter that is used has to match (any of its values can match).
+type Selection struct {
+	All   bool
+	IDs   []string
+	Names []string // patterns for the dependency names
+	Types []string
+	Paths []string // manifest, lockfile or dependency config paths
+}
+
+// IsEmpty is true when nothing was selected, so the prompt should be used
+func (s *Selection) IsEmpty() bool {
+	return s == nil || (!s.All && len(s.IDs) == 0 && len(s.Names) == 0 && len(s.Types) == 0 && len(s.Paths) == 0)
+}
+
+func (s *Selection) Validate() error {
+	for _, name := range s.Names {
+		if _, err := regexp.Compile(name); err != nil {
+			return fmt.Errorf("Invalid name pattern \"%s\": %v", name, err)
+		}
+	}
+	return nil
+}
+
+// matches checks the update against each kind of filter, All doesn't
+// filter anything on its own so the others still apply with it
+func (s *Selection) matches(update *Update) bool {
+	if len(s.IDs) > 0 && !containsString(s.IDs, update.id) {
+		return false
+	}
+
+	if len(s.Types) > 0 {
+		matched := false
+		for _, part := range update.actParts() {
+			if containsString(s.Types, part.dependencyConfig.Type) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(s.Paths) > 0 && !s.matchesPath(update) {
+		return false
+	}
+
+	if len(s.Names) > 0 && !s.matchesName(update) {
+		return false
+	}
+
+	return true
+}
+
+func (s *Selection) matchesPath(update *Update) bool {
+	paths := []string{}
+	for path := range update.dependencies.Lockfiles {
+		paths = append(paths, path)
+	}
+	for path := range update.dependencies.Manifests {
+		paths = append(paths, path)
+	}
+	for _, part := range update.actParts() {
+		paths = append(paths, part.dependencyConfig.Path)
+	}
+
+	for _, selected := range s.Paths {
+		selected = filepath.Clean(selected)
+		for _, path := range paths {
+			path = filepath.Clean(path)
+			if path == selected || strings.HasPrefix(path, selected+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Selection) matchesName(update *Update) bool {
+	names := []string{}
+	for _, lockfile := range update.dependencies.Lockfiles {
+		for name := range lockfile.UpdateTypes() {
+			names = append(names, name)
+		}
+	}
+	for _, manifest := range update.dependencies.Manifests {
+		for name := range manifest.Updated.Dependencies {
+			names = append(names, name)
+		}
+	}
+
+	for _, pattern := range s.Names {
+		re := regexp.MustCompile(pattern)
+		for _, name := range names {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"strings"
+
+	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/internal/pullrequest"
+)
+
+const staleComment = "This update is no longer needed (the dependencies were updated some other way, or a newer update replaced it), so deps is closing it and deleting the branch."
+
+// findStaleBranches finds the deps branches that don't
+// belong to any of the updates that were collected
+func findStaleBranches(updates Updates, branches []string) []string {
+	stale := []string{}
+
+	for _, branch := range branches {
+		found := false
+		for _, update := range updates {
+			// outdated branches are matched by id, like in organizeUpdates
+			if branch == update.branch || strings.HasPrefix(branch, git.GetBranchName(update.id)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			stale = append(stale, branch)
+		}
+	}
+
+	return stale
+}
+
+// closeStaleUpdates closes the pull requests for the stale branches and
+// deletes them, continuing after failures
+func closeStaleUpdates(repo pullrequest.RepoAdapter, branches []string) {
+	for _, branch := range branches {
+		output.Event("Closing stale update: %s", branch)
+
+		closed, err := repo.CloseStale(branch, staleComment)
+		for _, url := range closed {
+			output.Event("Closed %s", url)
+		}
+		if err != nil {
+			output.Error("Unable to close the pull request for %s: %v", branch, err)
+			continue
+		}
+
+		if err := git.DeleteRemoteBranch(branch); err != nil {
+			output.Error("Unable to delete branch %s: %v", branch, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindStaleBranches(t *testing.T) {
+	updates := Updates{
+		"update-abc": &Update{id: "update-abc", branch: "deps/update-abc-111"},
+		"update-def": &Update{id: "update-def", branch: "deps/update-def-222"},
+	}
+
+	tests := []struct {
+		name     string
+		branches []string
+		stale    []string
+	}{
+		{
+			name:     "current branches",
+			branches: []string{"deps/update-abc-111", "deps/update-def-222"},
+			stale:    []string{},
+		},
+		{
+			name:     "outdated branch matches by id",
+			branches: []string{"deps/update-abc-000"},
+			stale:    []string{},
+		},
+		{
+			name:     "no matching update",
+			branches: []string{"deps/update-abc-111", "deps/update-xyz-333"},
+			stale:    []string{"deps/update-xyz-333"},
+		},
+		{
+			name:     "id is only a prefix",
+			branches: []string{"deps/update-ab-444"},
+			stale:    []string{"deps/update-ab-444"},
+		},
+	}
+
+	for _, test := range tests {
+		if stale := findStaleBranches(updates, test.branches); !reflect.DeepEqual(stale, test.stale) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.stale, stale)
+		}
+	}
+}
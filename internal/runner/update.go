@@ -2,12 +2,14 @@ package runner
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/dropseed/deps/internal/schemaext"
 
 	"github.com/dropseed/deps/internal/component"
 	"github.com/dropseed/deps/internal/config"
 	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
 	"github.com/dropseed/deps/pkg/schema"
 )
 
@@ -20,14 +22,31 @@ type Update struct {
 	id               string
 	title            string
 	branch           string
+	// group is the group_name that this update can be combined with
+	group string
+	// parts are set when the update combines multiple dependency entries,
+	// each of which needs to be acted on by its own runner
+	parts []*updatePart
+}
+
+type updatePart struct {
+	dependencies     *schema.Dependencies
+	dependencyConfig *config.Dependency
+	runner           *component.Runner
 }
 
 func NewUpdate(deps *schema.Dependencies, cfg *config.Dependency) *Update {
+	return newUpdateVariant(deps, cfg, "")
+}
+
+// newUpdateVariant creates an update with an ID that won't collide with
+// other updates for the same files (a split lockfile, for example)
+func newUpdateVariant(deps *schema.Dependencies, cfg *config.Dependency, variant string) *Update {
 	if err := deps.Validate(); err != nil {
 		panic(err)
 	}
 
-	updateID := schemaext.UpdateIDForDeps(deps)
+	updateID := schemaext.UpdateIDForDepsVariant(deps, variant)
 	uniqueID := schemaext.UniqueIDForDeps(deps)
 	branch := git.GetBranchName(fmt.Sprintf("%s-%s", updateID, uniqueID))
 
@@ -35,19 +54,130 @@ func NewUpdate(deps *schema.Dependencies, cfg *config.Dependency) *Update {
 		dependencies:     deps,
 		dependencyConfig: cfg,
 		id:               updateID,
-		title:            schemaext.TitleForDeps(deps),
+		title:            schemaext.TitleForDeps(deps, cfg.LockfileUpdates.Split),
 		branch:           branch,
 	}
 
 	return &update
 }
 
-func (update *Update) exists() bool {
-	b := git.BranchMatching(update.branch)
-	return b != ""
+func (update *Update) exists() (bool, error) {
+	b, err := git.BranchMatching(update.branch)
+	return b != "", err
 }
 
-func (update *Update) outdatedBranch() string {
+func (update *Update) outdatedBranch() (string, error) {
 	// update id match only
 	return git.BranchMatching(git.GetBranchName(update.id))
 }
+
+// canCombine checks if two updates are part of the same named group
+func (update *Update) canCombine(other *Update) bool {
+	return update.group != "" && update.group == other.group && update.dependencyConfig.Type == other.dependencyConfig.Type
+}
+
+// combine creates a new update from the dependencies of both updates
+func (update *Update) combine(other *Update) *Update {
+	parts := update.actParts()
+	for _, part := range other.actParts() {
+		combined := false
+		for _, existing := range parts {
+			if existing.dependencyConfig == part.dependencyConfig {
+				existing.dependencies = existing.dependencies.Merge(part.dependencies)
+				combined = true
+				break
+			}
+		}
+		if !combined {
+			parts = append(parts, part)
+		}
+	}
+
+	// The first dependency entry is used for the pull request settings
+	combined := NewUpdate(update.dependencies.Merge(other.dependencies), update.dependencyConfig)
+	combined.group = update.group
+	combined.runner = update.runner
+	if len(parts) > 1 {
+		combined.parts = parts
+	}
+	return combined
+}
+
+func (update *Update) actParts() []*updatePart {
+	if len(update.parts) > 0 {
+		parts := make([]*updatePart, 0, len(update.parts))
+		for _, part := range update.parts {
+			copied := *part
+			parts = append(parts, &copied)
+		}
+		return parts
+	}
+	return []*updatePart{
+		&updatePart{
+			dependencies:     update.dependencies,
+			dependencyConfig: update.dependencyConfig,
+			runner:           update.runner,
+		},
+	}
+}
+
+// act runs the component(s) for the update and returns the combined output
+func (update *Update) act() (*schema.Dependencies, error) {
+	return update.actIn("", nil)
+}
+
+// actIn runs the components in another directory (the current one if empty)
+// with their output going to logger. The runners are copied so that
+// updates from the same dependency entry can run at the same time.
+func (update *Update) actIn(dir string, logger *output.Logger) (*schema.Dependencies, error) {
+	var outputDeps *schema.Dependencies
+
+	for _, part := range update.actParts() {
+		runner := *part.runner
+		runner.Dir = dir
+		if logger != nil {
+			runner.Logger = logger
+		}
+		partOutput, err := runner.Act(part.dependencies)
+		if err != nil {
+			return nil, err
+		}
+		if outputDeps == nil {
+			outputDeps = partOutput
+		} else {
+			outputDeps = outputDeps.Merge(partOutput)
+		}
+	}
+
+	return outputDeps, nil
+}
+
+// commitMessage is the message for committing the output of the update,
+// using the commit_prefix and commit_trailers settings
+func (update *Update) commitMessage(outputDeps *schema.Dependencies) (string, error) {
+	prefix := ""
+	if v := update.dependencyConfig.GetSettingForSchema("commit_prefix", outputDeps); v != nil {
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("commit_prefix setting must be a string, got %v", v)
+		}
+		prefix = s
+	}
+
+	trailers := []string{}
+	if v := update.dependencyConfig.GetSettingForSchema("commit_trailers", outputDeps); v != nil {
+		items, ok := v.([]interface{})
+		if !ok {
+			return "", fmt.Errorf("commit_trailers setting must be a list, got %v", v)
+		}
+		for _, item := range items {
+			trailer, ok := item.(string)
+			if !ok || !strings.Contains(trailer, ": ") {
+				return "", fmt.Errorf("commit_trailers must look like \"Key: value\", got %v", item)
+			}
+			trailers = append(trailers, trailer)
+		}
+	}
+
+	return schemaext.CommitMessageForDeps(outputDeps, update.dependencyConfig.LockfileUpdates.Split, prefix, trailers), nil
+}
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dropseed/deps/internal/config"
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+func updateWithSettings(t *testing.T, settings string) *Update {
+	cfg, err := config.NewConfigFromReader(strings.NewReader(`version: 3
+dependencies:
+- type: js
+  settings:
+` + settings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Compile()
+	return &Update{dependencyConfig: cfg.Dependencies[0]}
+}
+
+func testManifestDependencies() *schema.Dependencies {
+	return &schema.Dependencies{
+		Manifests: map[string]*schema.Manifest{
+			"package.json": &schema.Manifest{
+				Current: &schema.ManifestVersion{
+					Dependencies: map[string]*schema.ManifestDependency{
+						"react": &schema.ManifestDependency{Constraint: "^16.0.0"},
+					},
+				},
+				Updated: &schema.ManifestVersion{
+					Dependencies: map[string]*schema.ManifestDependency{
+						"react": &schema.ManifestDependency{Constraint: "^17.0.0"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	update := updateWithSettings(t, `    commit_prefix: "chore(deps):"
+    commit_trailers: ["Changelog: dependency"]
+`)
+	message, err := update.commitMessage(testManifestDependencies())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `chore(deps): Update react in package.json from ^16.0.0 to ^17.0.0
+
+- react in package.json: ^16.0.0 → ^17.0.0
+
+Changelog: dependency`
+	if message != expected {
+		t.Errorf("unexpected message %q", message)
+	}
+}
+
+func TestCommitMessageInvalidSettings(t *testing.T) {
+	for _, settings := range []string{
+		"    commit_prefix: [chore]\n",
+		"    commit_trailers: \"Changelog: dependency\"\n",
+		"    commit_trailers: [\"not a trailer\"]\n",
+		"    commit_trailers: [1]\n",
+	} {
+		if _, err := updateWithSettings(t, settings).commitMessage(testManifestDependencies()); err == nil {
+			t.Errorf("expected an error for %s", settings)
+		}
+	}
+}
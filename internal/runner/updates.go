@@ -1,6 +1,9 @@
 package runner
 
 import (
+	"sort"
+	"time"
+
 	"github.com/dropseed/deps/internal/config"
 	"github.com/dropseed/deps/internal/output"
 	"github.com/dropseed/deps/pkg/schema"
@@ -13,10 +16,55 @@ func (updates Updates) add(deps *schema.Dependencies, cfg *config.Dependency) {
 	updates.addUpdate(update)
 }
 
+func (updates Updates) addVariant(deps *schema.Dependencies, cfg *config.Dependency, variant string) {
+	update := newUpdateVariant(deps, cfg, variant)
+	updates.addUpdate(update)
+}
+
+func (updates Updates) addToGroup(deps *schema.Dependencies, cfg *config.Dependency, group string) {
+	update := NewUpdate(deps, cfg)
+	update.group = group
+	updates.addUpdate(update)
+}
+
 func (updates Updates) addUpdate(update *Update) {
+	for id, existing := range updates {
+		if existing.canCombine(update) {
+			delete(updates, id)
+			update = existing.combine(update)
+			break
+		}
+	}
 	updates[update.id] = update
 }
 
+// combineAll merges every update into one, in the order of the config
+// so that the first dependency entry is acted on first
+func (updates Updates) combineAll() Updates {
+	sorted := updates.sorted()
+	combined := sorted[0]
+	for _, update := range sorted[1:] {
+		combined = combined.combine(update)
+	}
+
+	return Updates{combined.id: combined}
+}
+
+// sorted orders the updates by their dependency entry and then ID
+func (updates Updates) sorted() []*Update {
+	sorted := make([]*Update, 0, len(updates))
+	for _, update := range updates {
+		sorted = append(sorted, update)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].runner.Index != sorted[j].runner.Index {
+			return sorted[i].runner.Index < sorted[j].runner.Index
+		}
+		return sorted[i].id < sorted[j].id
+	})
+	return sorted
+}
+
 func (updates Updates) printOverview() {
 	if len(updates) < 1 {
 		output.Success("No updates found")
@@ -29,6 +77,7 @@ func (updates Updates) printOverview() {
 
 func newUpdatesFromDependencies(dependencies *schema.Dependencies, dependencyConfig *config.Dependency) (Updates, error) {
 	updates := Updates{}
+	now := time.Now()
 
 	if *dependencyConfig.LockfileUpdates.Enabled {
 		output.Debug("Filtering lockfile updates")
@@ -38,15 +87,26 @@ func newUpdatesFromDependencies(dependencies *schema.Dependencies, dependencyCon
 				continue
 			}
 
-			// All lockfile updates are split out individually
+			ignored, err := ignoredLockfileDependencies(path, lockfile, dependencyConfig, now)
+			if err != nil {
+				return nil, err
+			}
 
-			updateDependencies := schema.Dependencies{
-				Lockfiles: map[string]*schema.Lockfile{
-					path: lockfile,
-				},
+			filteredLockfiles, err := dependencyConfig.LockfileUpdates.FilteredLockfiles(lockfile, ignored)
+			if err != nil {
+				return nil, err
 			}
 
-			updates.add(&updateDependencies, dependencyConfig)
+			// Each lockfile gets its own update(s)
+			for variant, filteredLockfile := range filteredLockfiles {
+				updateDependencies := schema.Dependencies{
+					Lockfiles: map[string]*schema.Lockfile{
+						path: filteredLockfile,
+					},
+				}
+
+				updates.addVariant(&updateDependencies, dependencyConfig, variant)
+			}
 		}
 	} else {
 		output.Event("Lockfile updates disbled")
@@ -60,12 +120,21 @@ func newUpdatesFromDependencies(dependencies *schema.Dependencies, dependencyCon
 				continue
 			}
 
-			filteredGroups, err := dependencyConfig.ManifestUpdates.FilteredDependencyGroups(manifest.Updated.Dependencies)
+			manifest, err := withoutIgnoredManifestDependencies(path, manifest, dependencyConfig, now)
 			if err != nil {
 				return nil, err
 			}
 
-			for _, groupDeps := range filteredGroups {
+			if !manifest.HasUpdates() {
+				continue
+			}
+
+			filteredGroups, err := dependencyConfig.ManifestUpdates.FilteredDependencyGroups(manifest)
+			if err != nil {
+				return nil, err
+			}
+
+			for groupKey, groupDeps := range filteredGroups {
 
 				updateDependencies := schema.Dependencies{
 					Manifests: map[string]*schema.Manifest{
@@ -86,7 +155,11 @@ func newUpdatesFromDependencies(dependencies *schema.Dependencies, dependencyCon
 					updateDependencies.Manifests[path].Updated.Dependencies[name] = dep
 				}
 
-				updates.add(&updateDependencies, dependencyConfig)
+				if group := config.NamedGroup(groupKey); group != "" {
+					updates.addToGroup(&updateDependencies, dependencyConfig, group)
+				} else {
+					updates.add(&updateDependencies, dependencyConfig)
+				}
 			}
 		}
 	} else {
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dropseed/deps/internal/config"
+	"github.com/dropseed/deps/internal/git"
+	"github.com/dropseed/deps/internal/output"
+	"github.com/dropseed/deps/internal/pullrequest"
+)
+
+// Worktrees makes each update in its own git worktree instead of the
+// current working copy, so that up to Jobs updates can be made at once
+var Worktrees = false
+
+type worktreeJob struct {
+	index    int
+	update   *Update
+	category string
+}
+
+func performUpdatesInWorktrees(newUpdates, outdatedUpdates Updates, base, outdatedBranches string) []*updateResult {
+	jobs := []*worktreeJob{}
+	for _, update := range newUpdates.sorted() {
+		jobs = append(jobs, &worktreeJob{index: len(jobs), update: update, category: categoryNew})
+	}
+	for _, update := range outdatedUpdates.sorted() {
+		jobs = append(jobs, &worktreeJob{index: len(jobs), update: update, category: categoryOutdated})
+	}
+
+	workers := Jobs
+	if workers < 1 {
+		workers = 1
+	}
+
+	output.Event("Performing %d updates in worktrees, %d at a time", len(jobs), workers)
+
+	results := make([]*updateResult, len(jobs))
+	queue := make(chan *worktreeJob)
+	flushing := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				var logger *output.Logger
+				if workers > 1 {
+					// keep the output for each update together
+					logger = output.NewLogger()
+				}
+
+				logger.Event("Running update: %s", job.update.title)
+				results[job.index] = recordUpdate(job.update, job.category, logger, func() (string, error) {
+					return runUpdateInWorktree(job.update, base, job.update.branch, job.category == categoryOutdated, outdatedBranches, logger)
+				})
+
+				flushing.Lock()
+				logger.Flush()
+				flushing.Unlock()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// runUpdateInWorktree is the same as runUpdate, but it doesn't
+// touch the current working copy
+func runUpdateInWorktree(update *Update, base, head string, existingUpdate bool, outdatedBranches string, logger *output.Logger) (string, error) {
+	recreate := existingUpdate && outdatedBranches == config.OutdatedBranchesRecreate
+	rebase := existingUpdate && outdatedBranches == config.OutdatedBranchesRebase
+
+	startPoint := base
+	if existingUpdate && !recreate {
+		// check out the existing branch
+		startPoint = ""
+	}
+
+	worktree, err := git.AddBranchWorktree(head, startPoint)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := worktree.Remove(); err != nil {
+			logger.Error("Error removing worktree %s: %v", worktree.Path, err)
+		}
+	}()
+
+	if rebase {
+		if err := worktree.Rebase(base); err != nil {
+			return "", fmt.Errorf("Unable to rebase %s onto %s: %v", head, base, err)
+		}
+	}
+
+	outputDeps, err := update.actIn(worktree.Path, logger)
+	if err != nil {
+		return "", err
+	}
+
+	pr, err := pullrequest.NewPullrequest(base, head, outputDeps, update.dependencyConfig)
+	if err != nil {
+		return "", err
+	}
+
+	dirty, err := worktree.IsDirty()
+	if err != nil {
+		return "", err
+	}
+	if !dirty {
+		if existingUpdate && !recreate {
+			logger.Event("No new changes to commit")
+			if rebase {
+				// the rebased branch still needs to be pushed
+				return "", worktree.ForcePushBranch(head)
+			}
+			return "", nil
+		}
+
+		return "", errors.New("Update didn't generate any changes to commit")
+	}
+
+	message, err := update.commitMessage(outputDeps)
+	if err != nil {
+		return "", err
+	}
+	if err := worktree.Commit(message); err != nil {
+		return "", err
+	}
+
+	if recreate || rebase {
+		err = worktree.ForcePushBranch(head)
+	} else {
+		err = worktree.PushBranch(head)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if pr != nil {
+		logger.Debug("Waiting a second for the push to be processed by the host")
+		time.Sleep(2 * time.Second)
+
+		if err := pr.CreateOrUpdate(); err != nil {
+			return "", err
+		}
+
+		return pr.GetURL(), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,111 @@
+// Package sarif writes the subset of SARIF 2.1.0 that code scanning tools need
+package sarif
+
+const Version = "2.1.0"
+const Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Levels for results
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []*Run `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool      `json:"tool"`
+	Results []*Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string  `json:"name"`
+	InformationURI string  `json:"informationUri,omitempty"`
+	Rules          []*Rule `json:"rules"`
+}
+
+type Rule struct {
+	ID               string  `json:"id"`
+	ShortDescription Message `json:"shortDescription"`
+}
+
+type Result struct {
+	RuleID    string      `json:"ruleId"`
+	Level     string      `json:"level"`
+	Message   Message     `json:"message"`
+	Locations []*Location `json:"locations"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// NewLog creates a log with a single run for the tool
+func NewLog(toolName, informationURI string) *Log {
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []*Run{
+			&Run{
+				Tool: Tool{
+					Driver: Driver{
+						Name:           toolName,
+						InformationURI: informationURI,
+						Rules:          []*Rule{},
+					},
+				},
+				Results: []*Result{},
+			},
+		},
+	}
+}
+
+// AddRule defines a rule, if it hasn't been already
+func (log *Log) AddRule(id, description string) {
+	driver := &log.Runs[0].Tool.Driver
+	for _, rule := range driver.Rules {
+		if rule.ID == id {
+			return
+		}
+	}
+	driver.Rules = append(driver.Rules, &Rule{
+		ID:               id,
+		ShortDescription: Message{Text: description},
+	})
+}
+
+// AddResult adds a result for a file
+func (log *Log) AddResult(ruleID, level, message, uri string) {
+	log.Runs[0].Results = append(log.Runs[0].Results, &Result{
+		RuleID:  ruleID,
+		Level:   level,
+		Message: Message{Text: message},
+		Locations: []*Location{
+			&Location{
+				PhysicalLocation: PhysicalLocation{
+					ArtifactLocation: ArtifactLocation{URI: uri},
+				},
+			},
+		},
+	})
+}
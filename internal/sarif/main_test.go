@@ -0,0 +1,27 @@
+package sarif
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	log := NewLog("deps", "")
+	log.AddRule("outdated-dependency", "Dependency is outdated")
+	log.AddRule("outdated-dependency", "Dependency is outdated")
+	log.AddResult("outdated-dependency", LevelWarning, "react can be updated", "package.json")
+
+	if len(log.Runs[0].Tool.Driver.Rules) != 1 {
+		t.Error("rules should be unique")
+	}
+
+	out, err := json.Marshal(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `"locations":[{"physicalLocation":{"artifactLocation":{"uri":"package.json"}}}]`
+	if !strings.Contains(string(out), expected) {
+		t.Error(string(out))
+	}
+}
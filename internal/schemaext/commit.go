@@ -0,0 +1,124 @@
+package schemaext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+// CommitMessageForDeps is the title (after an optional prefix like "chore(deps):"),
+// a plain text line for each changed dependency and then any trailers
+func CommitMessageForDeps(s *schema.Dependencies, splitLockfiles bool, prefix string, trailers []string) string {
+	title := TitleForDeps(s, splitLockfiles)
+	if prefix = strings.TrimSpace(prefix); prefix != "" {
+		title = prefix + " " + title
+	}
+
+	message := title
+
+	if lines := commitLinesForDeps(s); len(lines) > 0 {
+		message += "\n\n" + strings.Join(lines, "\n")
+	}
+
+	if len(trailers) > 0 {
+		message += "\n\n" + strings.Join(trailers, "\n")
+	}
+
+	return message
+}
+
+func commitLinesForDeps(s *schema.Dependencies) []string {
+	lines := []string{}
+
+	lockfilePaths := []string{}
+	for path, lockfile := range s.Lockfiles {
+		if lockfile.HasUpdates() {
+			lockfilePaths = append(lockfilePaths, path)
+		}
+	}
+	sort.Strings(lockfilePaths)
+
+	for _, path := range lockfilePaths {
+		lines = append(lines, commitLinesForLockfile(s.Lockfiles[path], path)...)
+	}
+
+	manifestPaths := []string{}
+	for path, manifest := range s.Manifests {
+		if manifest.HasUpdates() {
+			manifestPaths = append(manifestPaths, path)
+		}
+	}
+	sort.Strings(manifestPaths)
+
+	for _, path := range manifestPaths {
+		lines = append(lines, commitLinesForManifest(s.Manifests[path], path)...)
+	}
+
+	return lines
+}
+
+func commitLinesForLockfile(lockfile *schema.Lockfile, lockfilePath string) []string {
+	changesByType := lockfileChangesByType(lockfile)
+
+	direct := &LockfileChanges{}
+	numTransitive := 0
+
+	if changes, found := changesByType["direct"]; found {
+		direct = changes
+	}
+	if changes, found := changesByType["transitive"]; found {
+		numTransitive = len(changes.Updated) + len(changes.Added) + len(changes.Removed)
+	}
+
+	numDirect := len(direct.Updated) + len(direct.Added) + len(direct.Removed)
+
+	lines := []string{
+		fmt.Sprintf("- %s: %d direct and %d transitive dependencies changed", lockfilePath, numDirect, numTransitive),
+	}
+
+	sort.Strings(direct.Updated)
+	for _, name := range direct.Updated {
+		current := lockfile.Current.Dependencies[name].Version
+		updated := lockfile.Updated.Dependencies[name].Version
+		lines = append(lines, fmt.Sprintf("  - %s %s → %s%s", name, current.Name, updated.Name, versionNotes(current, updated)))
+	}
+
+	sort.Strings(direct.Added)
+	for _, name := range direct.Added {
+		updated := lockfile.Updated.Dependencies[name].Version
+		lines = append(lines, fmt.Sprintf("  - %s %s added%s", name, updated.Name, versionNotes(nil, updated)))
+	}
+
+	sort.Strings(direct.Removed)
+	for _, name := range direct.Removed {
+		current := lockfile.Current.Dependencies[name].Version
+		lines = append(lines, fmt.Sprintf("  - %s %s removed", name, current.Name))
+	}
+
+	return lines
+}
+
+func commitLinesForManifest(manifest *schema.Manifest, manifestPath string) []string {
+	names := []string{}
+	for name := range manifest.Updated.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inManifest := ""
+	if manifestPath != "" {
+		inManifest = fmt.Sprintf(" in %s", manifestPath)
+	}
+
+	lines := []string{}
+	for _, name := range names {
+		current := manifest.Current.Dependencies[name]
+		updated := manifest.Updated.Dependencies[name]
+		notes := versionNotes(current.Version, updated.Version)
+		lines = append(lines, fmt.Sprintf("- %s%s: %s → %s%s", dependencyNameForDisplay(name), inManifest, current.Constraint, updated.Constraint, notes))
+	}
+
+	return lines
+}
@@ -13,7 +13,7 @@ func generateTitleFromFilename(filename string) (string, error) {
 		return "", err
 	}
 
-	return TitleForDeps(dependencies), nil
+	return TitleForDeps(dependencies, false), nil
 }
 
 func TestMalformedJSON(t *testing.T) {
@@ -129,6 +129,20 @@ func TestGenerateBodyWithSingleDependencyEmptyManifest(t *testing.T) {
 	}
 }
 
+func TestGenerateBodyWithVersionMetadata(t *testing.T) {
+	body, err := generateBodyFromFilename("./testdata/single_dependency_metadata.json")
+	if err != nil {
+		t.Error(err)
+	}
+	expected, err := ioutil.ReadFile("./testdata/single_body_metadata.txt")
+	if err != nil {
+		panic(err)
+	}
+	if body != string(expected) {
+		t.Error("Body does not match expected: ", body)
+	}
+}
+
 func TestGenerateBodyWithTwoDependencies(t *testing.T) {
 	body, err := generateBodyFromFilename("./testdata/two_dependencies.json")
 	if err != nil {
@@ -198,3 +212,97 @@ func TestGenerateBodyWithOneLockfile(t *testing.T) {
 // 		}
 // 	}
 // }
+
+func generateCommitMessageFromFilename(filename string, prefix string, trailers []string) (string, error) {
+	dependencies, err := schema.NewDependenciesFromJSONPath(filename)
+	if err != nil {
+		return "", err
+	}
+	return CommitMessageForDeps(dependencies, false, prefix, trailers), nil
+}
+
+func TestCommitMessageWithTwoDependencies(t *testing.T) {
+	message, err := generateCommitMessageFromFilename("./testdata/two_dependencies.json", "", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	expected, err := ioutil.ReadFile("./testdata/two_commit.txt")
+	if err != nil {
+		panic(err)
+	}
+	if message != string(expected) {
+		t.Error("Commit message does not match expected: ", message)
+	}
+}
+
+func TestCommitMessageWithPrefixAndTrailers(t *testing.T) {
+	message, err := generateCommitMessageFromFilename("./testdata/single_lockfile.json", "chore(deps):", []string{"Changelog: dependency"})
+	if err != nil {
+		t.Error(err)
+	}
+	expected, err := ioutil.ReadFile("./testdata/single_lockfile_commit.txt")
+	if err != nil {
+		panic(err)
+	}
+	if message != string(expected) {
+		t.Error("Commit message does not match expected: ", message)
+	}
+}
+
+func TestGenerateTitleForTransitiveLockfile(t *testing.T) {
+	dependencies := &schema.Dependencies{
+		Lockfiles: map[string]*schema.Lockfile{
+			"yarn.lock": &schema.Lockfile{
+				Current: &schema.LockfileVersion{
+					Fingerprint: "a",
+					Dependencies: map[string]*schema.LockfileDependency{
+						"js-tokens": &schema.LockfileDependency{Version: &schema.Version{Name: "4.0.0"}, IsTransitive: true},
+					},
+				},
+				Updated: &schema.LockfileVersion{
+					Fingerprint: "b",
+					Dependencies: map[string]*schema.LockfileDependency{
+						"js-tokens": &schema.LockfileDependency{Version: &schema.Version{Name: "4.0.1"}, IsTransitive: true},
+					},
+				},
+			},
+		},
+	}
+
+	if title := TitleForDeps(dependencies, false); title != "Update yarn.lock" {
+		t.Error("Title does not match expected: ", title)
+	}
+	if title := TitleForDeps(dependencies, true); title != "Update transitive dependencies in yarn.lock" {
+		t.Error("Split title does not match expected: ", title)
+	}
+}
+
+func TestCommitMessageWithAddedAndRemovedDirectDependencies(t *testing.T) {
+	dependencies := &schema.Dependencies{
+		Lockfiles: map[string]*schema.Lockfile{
+			"yarn.lock": &schema.Lockfile{
+				Current: &schema.LockfileVersion{
+					Fingerprint: "a",
+					Dependencies: map[string]*schema.LockfileDependency{
+						"left-pad": &schema.LockfileDependency{Version: &schema.Version{Name: "1.1.0"}},
+					},
+				},
+				Updated: &schema.LockfileVersion{
+					Fingerprint: "b",
+					Dependencies: map[string]*schema.LockfileDependency{
+						"react": &schema.LockfileDependency{Version: &schema.Version{Name: "17.0.0"}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := `Update yarn.lock
+
+- yarn.lock: 2 direct and 0 transitive dependencies changed
+  - react 17.0.0 added
+  - left-pad 1.1.0 removed`
+	if message := CommitMessageForDeps(dependencies, false, "", nil); message != expected {
+		t.Error("Commit message does not match expected: ", message)
+	}
+}
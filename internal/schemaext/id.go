@@ -46,6 +46,19 @@ func UpdateIDForDeps(dependencies *schema.Dependencies) string {
 	return getShortMD5(truncated)
 }
 
+// UpdateIDForDepsVariant separates updates that would otherwise share an ID,
+// like the direct and transitive updates for a split lockfile
+func UpdateIDForDepsVariant(dependencies *schema.Dependencies, variant string) string {
+	updateID := UpdateIDForDeps(dependencies)
+	if variant == "" {
+		return updateID
+	}
+	return getShortMD5(map[string]string{
+		"id":      updateID,
+		"variant": variant,
+	})
+}
+
 func UniqueIDForDeps(dependencies *schema.Dependencies) string {
 	return getShortMD5(dependencies)
 }
@@ -98,7 +98,8 @@ func getSummaryLineForLockfile(lockfile *schema.Lockfile, lockfilePath string) (
 		for _, name := range direct.Updated {
 			currentDep := lockfile.Current.Dependencies[name]
 			dep := lockfile.Updated.Dependencies[name]
-			subitems += fmt.Sprintf("\n  - `%s` was updated from %s to %s", name, currentDep.Version.Name, dep.Version.Name)
+			notes := versionNotes(currentDep.Version, dep.Version)
+			subitems += fmt.Sprintf("\n  - `%s` was updated from %s to %s%s", name, currentDep.Version.Name, dep.Version.Name, notes)
 		}
 	}
 
@@ -10,7 +10,9 @@ import (
 
 const maxBodyLength = 65535
 
-func TitleForDeps(s *schema.Dependencies) string {
+// TitleForDeps is the title for an update. When lockfile updates are split,
+// a lockfile without direct changes is titled as the transitive update.
+func TitleForDeps(s *schema.Dependencies, splitLockfiles bool) string {
 
 	lockfiles := map[string]*schema.Lockfile{}
 	manifests := map[string]*schema.Manifest{}
@@ -49,6 +51,9 @@ func TitleForDeps(s *schema.Dependencies) string {
 		sort.Strings(lockfilePaths)
 
 		if len(lockfilePaths) == 1 {
+			if direct, found := lockfileChangesByType(lockfiles[lockfilePaths[0]])["direct"]; splitLockfiles && (!found || len(direct.Updated)+len(direct.Added) == 0) {
+				return fmt.Sprintf("Update transitive dependencies in %v", lockfilePaths[0])
+			}
 			return fmt.Sprintf("Update %v", lockfilePaths[0])
 		}
 		return fmt.Sprintf("Update lockfiles: %v", strings.Join(lockfilePaths, ", "))
@@ -113,6 +118,30 @@ func TitleForDeps(s *schema.Dependencies) string {
 }
 
 func DescriptionForDeps(s *schema.Dependencies) string {
+	summary := SummaryForDeps(s)
+	if summary == "" {
+		return ""
+	}
+
+	summaryHeader := "The following dependencies have been updated by [dependencies.io](https://www.dependencies.io/):\n\n"
+
+	notes := "" // TODO use go template instead
+	// notes := env.GetSetting("pullrequest_notes", "")
+	// if notes != "" {
+	// 	notes = notes + "\n\n---\n\n"
+	// }
+
+	final := notes + summaryHeader + summary + "\n"
+
+	if len(final) > maxBodyLength {
+		final = final[:maxBodyLength]
+	}
+
+	return final
+}
+
+// SummaryForDeps is a Markdown list of the changes to each lockfile and manifest
+func SummaryForDeps(s *schema.Dependencies) string {
 	lockfiles := map[string]*schema.Lockfile{}
 	manifests := map[string]*schema.Manifest{}
 
@@ -152,19 +181,5 @@ func DescriptionForDeps(s *schema.Dependencies) string {
 		summaryLines = append(summaryLines, lines...)
 	}
 
-	summaryHeader := "The following dependencies have been updated by [dependencies.io](https://www.dependencies.io/):\n\n"
-
-	notes := "" // TODO use go template instead
-	// notes := env.GetSetting("pullrequest_notes", "")
-	// if notes != "" {
-	// 	notes = notes + "\n\n---\n\n"
-	// }
-
-	final := notes + summaryHeader + strings.Join(summaryLines, "\n") + "\n"
-
-	if len(final) > maxBodyLength {
-		final = final[:maxBodyLength]
-	}
-
-	return final
+	return strings.Join(summaryLines, "\n")
 }
@@ -49,5 +49,6 @@ func getSummaryLineForDependencyName(manifest *schema.Manifest, name, manifestPa
 	if manifestPath != "" {
 		inManifest = fmt.Sprintf(" in `%s`", manifestPath)
 	}
-	return fmt.Sprintf("- `%s`%s from \"%s\" to \"%s\"", dependencyNameForDisplay(name), inManifest, currentDependency.Constraint, updatedDependency.Constraint), nil
+	notes := versionNotes(currentDependency.Version, updatedDependency.Version)
+	return fmt.Sprintf("- `%s`%s from \"%s\" to \"%s\"%s", dependencyNameForDisplay(name), inManifest, currentDependency.Constraint, updatedDependency.Constraint, notes), nil
 }
@@ -0,0 +1,61 @@
+package schemaext
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dropseed/deps/pkg/schema"
+)
+
+// versionNotes summarizes the optional version metadata as a parenthetical
+func versionNotes(current, updated *schema.Version) string {
+	if updated == nil {
+		return ""
+	}
+
+	notes := []string{}
+
+	if updated.ReleasedAt != nil {
+		notes = append(notes, fmt.Sprintf("released %s", updated.ReleasedAt.Format("2006-01-02")))
+	}
+	if updated.Yanked {
+		notes = append(notes, "yanked")
+	}
+	if updated.Deprecated {
+		notes = append(notes, "deprecated")
+	}
+
+	if current != nil {
+		fixed := []string{}
+		for _, advisory := range current.Advisories {
+			if !containsString(updated.Advisories, advisory) {
+				fixed = append(fixed, advisory)
+			}
+		}
+		if len(fixed) > 0 {
+			notes = append(notes, fmt.Sprintf("fixes %s", strings.Join(fixed, ", ")))
+		}
+	}
+	if len(updated.Advisories) > 0 {
+		notes = append(notes, fmt.Sprintf("affected by %s", strings.Join(updated.Advisories, ", ")))
+	}
+
+	if current != nil && current.License != "" && updated.License != "" && current.License != updated.License {
+		notes = append(notes, fmt.Sprintf("license changed from %s to %s", current.License, updated.License))
+	}
+
+	if len(notes) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s)", strings.Join(notes, ", "))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -3,12 +3,16 @@ package schema
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 )
 
 type Dependencies struct {
-	Lockfiles map[string]*Lockfile `json:"lockfiles,omitempty"`
-	Manifests map[string]*Manifest `json:"manifests,omitempty"`
+	SchemaVersion int                  `json:"schema_version,omitempty"`
+	Lockfiles     map[string]*Lockfile `json:"lockfiles,omitempty"`
+	Manifests     map[string]*Manifest `json:"manifests,omitempty"`
+	// Warnings are problems found while decoding that were safe to ignore
+	Warnings []string `json:"-"`
 }
 
 // NewDependenciesFromJSONPath loads Dependencies from a JSON file path
@@ -22,8 +26,52 @@ func NewDependenciesFromJSONPath(path string) (*Dependencies, error) {
 
 // NewDependenciesFromJSONContent creates a Dependencies instance with Unmarshalled JSON data
 func NewDependenciesFromJSONContent(content []byte) (*Dependencies, error) {
+	var raw interface{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	data, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, newValidationError("", "expected an object, got %s", jsonTypeName(raw))
+	}
+
+	version, err := schemaVersionFromData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []string{}
+	jsonSchema := NewJSONSchema()
+
+	if version < CurrentSchemaVersion {
+		if err := upgradeData(data, version); err != nil {
+			return nil, err
+		}
+	} else if version > CurrentSchemaVersion {
+		// Newer components can add fields that we don't know about yet,
+		// which we can ignore instead of failing entirely
+		warnings = append(warnings, fmt.Sprintf("Schema version %d is newer than the supported version %d, you may need to upgrade deps", version, CurrentSchemaVersion))
+		for _, path := range jsonSchema.removeUnknownFields("", data) {
+			warnings = append(warnings, fmt.Sprintf("Ignoring unknown field %s", path))
+		}
+	}
+
+	// Check against the JSON schema first so that errors point to
+	// the exact location in the data instead of a Go type
+	if err := jsonSchema.Validate(data); err != nil {
+		return nil, err
+	}
+
+	data["schema_version"] = CurrentSchemaVersion
+
+	upgraded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
 	deps := Dependencies{}
-	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder := json.NewDecoder(bytes.NewReader(upgraded))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&deps); err != nil {
 		return nil, err
@@ -33,18 +81,22 @@ func NewDependenciesFromJSONContent(content []byte) (*Dependencies, error) {
 		return nil, err
 	}
 
+	if len(warnings) > 0 {
+		deps.Warnings = warnings
+	}
+
 	return &deps, nil
 }
 
 func (s *Dependencies) Validate() error {
-	for _, lockfile := range s.Lockfiles {
+	for path, lockfile := range s.Lockfiles {
 		if err := lockfile.Validate(); err != nil {
-			return err
+			return prefixError(keyPath("lockfiles", path), err)
 		}
 	}
-	for _, manifest := range s.Manifests {
+	for path, manifest := range s.Manifests {
 		if err := manifest.Validate(); err != nil {
-			return err
+			return prefixError(keyPath("manifests", path), err)
 		}
 	}
 
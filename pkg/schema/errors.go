@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a problem at a specific location in the JSON data
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors collects every problem found in a single document
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, 0, len(errs))
+	for _, e := range errs {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newValidationError(path string, f string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf(f, args...),
+	}
+}
+
+// prefixError nests a validation error under the given path
+func prefixError(prefix string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ve, ok := err.(*ValidationError); ok {
+		return &ValidationError{
+			Path:    joinPath(prefix, ve.Path),
+			Message: ve.Message,
+		}
+	}
+	return &ValidationError{
+		Path:    prefix,
+		Message: err.Error(),
+	}
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	if strings.HasPrefix(path, "[") {
+		return prefix + path
+	}
+	return prefix + "." + path
+}
+
+func keyPath(prefix, key string) string {
+	return fmt.Sprintf("%s[%q]", prefix, key)
+}
@@ -0,0 +1,269 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema is the subset of JSON Schema needed to describe Dependencies
+type JSONSchema struct {
+	Schema     string                 `json:"$schema,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+	Type       string                 `json:"type,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *JSONSchema            `json:"items,omitempty"`
+	// AdditionalProperties is either false (structs) or a *JSONSchema (maps)
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+// NewJSONSchema generates the JSON schema for Dependencies from the Go types
+func NewJSONSchema() *JSONSchema {
+	s := jsonSchemaForType(reflect.TypeOf(Dependencies{}))
+	s.Schema = jsonSchemaDraft
+	s.Title = fmt.Sprintf("deps dependencies (schema version %d)", CurrentSchemaVersion)
+	return s
+}
+
+func jsonSchemaForType(t reflect.Type) *JSONSchema {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		s := &JSONSchema{
+			Type:                 "object",
+			Properties:           map[string]*JSONSchema{},
+			AdditionalProperties: false,
+		}
+		addStructProperties(s, t)
+		sort.Strings(s.Required)
+		return s
+	case reflect.Map:
+		return &JSONSchema{
+			Type:                 "object",
+			AdditionalProperties: jsonSchemaForType(t.Elem()),
+		}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{
+			Type:  "array",
+			Items: jsonSchemaForType(t.Elem()),
+		}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	}
+
+	panic(fmt.Sprintf("no JSON schema for type %s", t))
+}
+
+func addStructProperties(s *JSONSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty := jsonFieldName(field)
+
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			// embedded structs are flattened by encoding/json
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			addStructProperties(s, embedded)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = jsonSchemaForType(field.Type)
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	omitempty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty
+}
+
+// Validate checks decoded JSON (from json.Unmarshal into an interface{})
+// against the schema and returns every problem found
+func (s *JSONSchema) Validate(data interface{}) error {
+	errs := s.validate("", data)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *JSONSchema) validate(path string, data interface{}) ValidationErrors {
+	errs := ValidationErrors{}
+
+	switch s.Type {
+	case "object":
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return append(errs, newValidationError(path, "expected an object, got %s", jsonTypeName(data)))
+		}
+
+		for _, name := range s.Required {
+			if v, found := obj[name]; !found || v == nil {
+				errs = append(errs, newValidationError(joinPath(path, name), "is required"))
+			}
+		}
+
+		for _, key := range sortedKeys(obj) {
+			value := obj[key]
+			if prop, found := s.Properties[key]; found {
+				if value != nil {
+					errs = append(errs, prop.validate(joinPath(path, key), value)...)
+				}
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case *JSONSchema:
+				errs = append(errs, additional.validate(keyPath(path, key), value)...)
+			case bool:
+				if !additional {
+					errs = append(errs, newValidationError(joinPath(path, key), "unknown field"))
+				}
+			}
+		}
+	case "array":
+		arr, ok := data.([]interface{})
+		if !ok {
+			return append(errs, newValidationError(path, "expected an array, got %s", jsonTypeName(data)))
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	case "string":
+		if str, ok := data.(string); !ok {
+			errs = append(errs, newValidationError(path, "expected a string, got %s", jsonTypeName(data)))
+		} else if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				errs = append(errs, newValidationError(path, "expected an RFC 3339 date-time, got %q", str))
+			}
+		}
+	case "boolean":
+		if _, ok := data.(bool); !ok {
+			errs = append(errs, newValidationError(path, "expected a boolean, got %s", jsonTypeName(data)))
+		}
+	case "integer":
+		if f, ok := data.(float64); !ok || f != float64(int64(f)) {
+			errs = append(errs, newValidationError(path, "expected an integer, got %s", jsonTypeName(data)))
+		}
+	case "number":
+		if _, ok := data.(float64); !ok {
+			errs = append(errs, newValidationError(path, "expected a number, got %s", jsonTypeName(data)))
+		}
+	}
+
+	return errs
+}
+
+// removeUnknownFields deletes properties that the schema doesn't define
+// and returns their paths
+func (s *JSONSchema) removeUnknownFields(path string, data interface{}) []string {
+	removed := []string{}
+
+	switch s.Type {
+	case "object":
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return removed
+		}
+		for _, key := range sortedKeys(obj) {
+			if prop, found := s.Properties[key]; found {
+				removed = append(removed, prop.removeUnknownFields(joinPath(path, key), obj[key])...)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case *JSONSchema:
+				removed = append(removed, additional.removeUnknownFields(keyPath(path, key), obj[key])...)
+			case bool:
+				if !additional {
+					delete(obj, key)
+					removed = append(removed, joinPath(path, key))
+				}
+			}
+		}
+	case "array":
+		arr, ok := data.([]interface{})
+		if !ok || s.Items == nil {
+			return removed
+		}
+		for i, item := range arr {
+			removed = append(removed, s.Items.removeUnknownFields(fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+	}
+
+	return removed
+}
+
+func jsonTypeName(data interface{}) string {
+	switch data.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	}
+	return fmt.Sprintf("%T", data)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
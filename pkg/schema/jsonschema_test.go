@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestJSONSchemaRequired(t *testing.T) {
+	s := NewJSONSchema()
+	manifest := s.Properties["manifests"].AdditionalProperties.(*JSONSchema)
+	if len(manifest.Required) != 1 || manifest.Required[0] != "current" {
+		t.Error(manifest.Required)
+	}
+}
+
+func TestValidationErrorPath(t *testing.T) {
+	content := `{
+	"manifests": {
+		"package.json": {
+			"current": {"dependencies": {"react": {"source": "npm", "constraint": "^16.0.0"}}},
+			"updated": {"dependencies": {"react": {"source": "npm", "constraint": 17}}}
+		}
+	}
+}`
+	_, err := NewDependenciesFromJSONContent([]byte(content))
+	if err == nil {
+		t.FailNow()
+	}
+	expected := `manifests["package.json"].updated.dependencies["react"].constraint: expected a string, got a number`
+	if err.Error() != expected {
+		t.Error(err)
+	}
+}
+
+func TestValidationErrorUnknownField(t *testing.T) {
+	content := `{
+	"lockfiles": {
+		"yarn.lock": {
+			"current": {"fingerprint": "abc", "dependencies": {}, "extra": true}
+		}
+	}
+}`
+	_, err := NewDependenciesFromJSONContent([]byte(content))
+	if err == nil {
+		t.FailNow()
+	}
+	expected := `lockfiles["yarn.lock"].current.extra: unknown field`
+	if err.Error() != expected {
+		t.Error(err)
+	}
+}
+
+func TestValidationErrorEmptyValue(t *testing.T) {
+	content := `{
+	"manifests": {
+		"requirements.txt": {
+			"current": {"dependencies": {"django": {"source": "pypi", "constraint": ""}}}
+		}
+	}
+}`
+	_, err := NewDependenciesFromJSONContent([]byte(content))
+	if err == nil {
+		t.FailNow()
+	}
+	expected := `manifests["requirements.txt"].current.dependencies["django"].constraint: is required`
+	if err.Error() != expected {
+		t.Error(err)
+	}
+}
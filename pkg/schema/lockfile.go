@@ -1,9 +1,5 @@
 package schema
 
-import (
-	"errors"
-)
-
 type Lockfile struct {
 	Current *LockfileVersion `json:"current"`
 	Updated *LockfileVersion `json:"updated,omitempty"`
@@ -25,15 +21,15 @@ type LockfileDependency struct {
 func (lockfile *Lockfile) Validate() error {
 	if lockfile.Current != nil {
 		if err := lockfile.Current.Validate(); err != nil {
-			return err
+			return prefixError("current", err)
 		}
 	} else {
-		return errors.New("lockfile.current is required")
+		return newValidationError("current", "is required")
 	}
 
 	if lockfile.Updated != nil {
 		if err := lockfile.Updated.Validate(); err != nil {
-			return err
+			return prefixError("updated", err)
 		}
 	}
 
@@ -46,12 +42,12 @@ func (lockfile *Lockfile) HasUpdates() bool {
 
 func (lv *LockfileVersion) Validate() error {
 	if lv.Fingerprint == "" {
-		return errors.New("lockfile fingerprint is required")
+		return newValidationError("fingerprint", "is required")
 	}
 
-	for _, dependency := range lv.Dependencies {
+	for name, dependency := range lv.Dependencies {
 		if err := dependency.Validate(); err != nil {
-			return err
+			return prefixError(keyPath("dependencies", name), err)
 		}
 	}
 
@@ -61,10 +57,10 @@ func (lv *LockfileVersion) Validate() error {
 func (ld *LockfileDependency) Validate() error {
 	if ld.Version != nil {
 		if err := ld.Version.Validate(); err != nil {
-			return err
+			return prefixError("version", err)
 		}
 	} else {
-		return errors.New("lockfile dependency.version is required")
+		return newValidationError("version", "is required")
 	}
 	return nil
 }
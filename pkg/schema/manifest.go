@@ -1,9 +1,5 @@
 package schema
 
-import (
-	"errors"
-)
-
 // Manifest contains manifest data
 type Manifest struct {
 	Current *ManifestVersion `json:"current"`
@@ -19,21 +15,23 @@ type ManifestVersion struct {
 
 type ManifestDependency struct {
 	Constraint string `json:"constraint"`
+	// Version is optionally the version that the constraint resolves to
+	Version *Version `json:"version,omitempty"`
 	*Dependency
 }
 
 func (manifest *Manifest) Validate() error {
 	if manifest.Current != nil {
 		if err := manifest.Current.Validate(); err != nil {
-			return err
+			return prefixError("current", err)
 		}
 	} else {
-		return errors.New("manifest.current is requried")
+		return newValidationError("current", "is required")
 	}
 
 	if manifest.Updated != nil {
 		if err := manifest.Updated.Validate(); err != nil {
-			return err
+			return prefixError("updated", err)
 		}
 	}
 
@@ -45,16 +43,21 @@ func (manifest *Manifest) HasUpdates() bool {
 }
 
 func (mv *ManifestVersion) Validate() error {
-	for _, dependency := range mv.Dependencies {
+	for name, dependency := range mv.Dependencies {
 		if err := dependency.Validate(); err != nil {
-			return err
+			return prefixError(keyPath("dependencies", name), err)
 		}
 	}
 	return nil
 }
 func (md *ManifestDependency) Validate() error {
 	if md.Constraint == "" {
-		return errors.New("manifest dependency constraint is required")
+		return newValidationError("constraint", "is required")
+	}
+	if md.Version != nil {
+		if err := md.Version.Validate(); err != nil {
+			return prefixError("version", err)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,141 @@
+package schema
+
+// Merge combines two sets of dependencies into a new one. Manifests and
+// lockfiles found in both are combined, with dependencies from other taking
+// precedence.
+func (s *Dependencies) Merge(other *Dependencies) *Dependencies {
+	merged := &Dependencies{
+		SchemaVersion: s.SchemaVersion,
+	}
+
+	for _, deps := range []*Dependencies{s, other} {
+		for path, lockfile := range deps.Lockfiles {
+			if merged.Lockfiles == nil {
+				merged.Lockfiles = map[string]*Lockfile{}
+			}
+			if existing, found := merged.Lockfiles[path]; found {
+				lockfile = existing.merge(lockfile)
+			}
+			merged.Lockfiles[path] = lockfile
+		}
+		for path, manifest := range deps.Manifests {
+			if merged.Manifests == nil {
+				merged.Manifests = map[string]*Manifest{}
+			}
+			if existing, found := merged.Manifests[path]; found {
+				manifest = &Manifest{
+					LockfilePath: existing.LockfilePath,
+					Current:      existing.Current.merge(manifest.Current),
+					Updated:      existing.Updated.merge(manifest.Updated),
+				}
+			}
+			merged.Manifests[path] = manifest
+		}
+		merged.Warnings = append(merged.Warnings, deps.Warnings...)
+	}
+
+	return merged
+}
+
+// merge combines two sets of changes to the same lockfile. Each dependency is
+// taken from whichever one changed it (other if both did), so that the direct
+// and transitive variants of a split lockfile can be put back together.
+func (lockfile *Lockfile) merge(other *Lockfile) *Lockfile {
+	merged := &Lockfile{
+		Current: lockfile.Current.merge(other.Current),
+	}
+	if lockfile.Updated == nil || other.Updated == nil {
+		merged.Updated = lockfile.Updated.merge(other.Updated)
+		return merged
+	}
+
+	merged.Updated = &LockfileVersion{
+		Fingerprint:  other.Updated.Fingerprint,
+		Dependencies: map[string]*LockfileDependency{},
+	}
+
+	names := map[string]bool{}
+	for _, lv := range []*LockfileVersion{merged.Current, lockfile.Updated, other.Updated} {
+		if lv != nil {
+			for name := range lv.Dependencies {
+				names[name] = true
+			}
+		}
+	}
+
+	for name := range names {
+		var dep *LockfileDependency
+		switch {
+		case other.changed(name):
+			dep = other.Updated.Dependencies[name]
+		case lockfile.changed(name):
+			dep = lockfile.Updated.Dependencies[name]
+		case other.Updated.Dependencies[name] != nil:
+			dep = other.Updated.Dependencies[name]
+		default:
+			dep = lockfile.Updated.Dependencies[name]
+		}
+		if dep != nil {
+			merged.Updated.Dependencies[name] = dep
+		}
+	}
+
+	return merged
+}
+
+// changed checks if a dependency was added, removed or has a different version
+func (lockfile *Lockfile) changed(name string) bool {
+	var current, updated *LockfileDependency
+	if lockfile.Current != nil {
+		current = lockfile.Current.Dependencies[name]
+	}
+	if lockfile.Updated != nil {
+		updated = lockfile.Updated.Dependencies[name]
+	}
+	if current == nil || updated == nil {
+		return current != updated
+	}
+	if current.Version == nil || updated.Version == nil {
+		return current.Version != updated.Version
+	}
+	return current.Version.Name != updated.Version.Name
+}
+
+func (lv *LockfileVersion) merge(other *LockfileVersion) *LockfileVersion {
+	if lv == nil {
+		return other
+	}
+	if other == nil {
+		return lv
+	}
+	merged := &LockfileVersion{
+		Fingerprint:  other.Fingerprint,
+		Dependencies: map[string]*LockfileDependency{},
+	}
+	for name, dep := range lv.Dependencies {
+		merged.Dependencies[name] = dep
+	}
+	for name, dep := range other.Dependencies {
+		merged.Dependencies[name] = dep
+	}
+	return merged
+}
+
+func (mv *ManifestVersion) merge(other *ManifestVersion) *ManifestVersion {
+	if mv == nil {
+		return other
+	}
+	if other == nil {
+		return mv
+	}
+	merged := &ManifestVersion{
+		Dependencies: map[string]*ManifestDependency{},
+	}
+	for name, dep := range mv.Dependencies {
+		merged.Dependencies[name] = dep
+	}
+	for name, dep := range other.Dependencies {
+		merged.Dependencies[name] = dep
+	}
+	return merged
+}
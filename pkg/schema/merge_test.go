@@ -0,0 +1,55 @@
+package schema
+
+import "testing"
+
+func lockfileVersion(versions map[string]string) *LockfileVersion {
+	lv := &LockfileVersion{
+		Fingerprint:  "abc",
+		Dependencies: map[string]*LockfileDependency{},
+	}
+	for name, version := range versions {
+		lv.Dependencies[name] = &LockfileDependency{
+			Version:      &Version{Name: version},
+			IsTransitive: name != "react",
+		}
+	}
+	return lv
+}
+
+func TestMergeSplitLockfile(t *testing.T) {
+	current := lockfileVersion(map[string]string{"react": "16.0.0", "loose-envify": "1.0.0", "js-tokens": "3.0.0"})
+
+	// the direct and transitive variants of one lockfile,
+	// each with the current version of what the other changed
+	direct := &Dependencies{Lockfiles: map[string]*Lockfile{
+		"yarn.lock": &Lockfile{
+			Current: current,
+			Updated: lockfileVersion(map[string]string{"react": "17.0.0", "loose-envify": "1.0.0", "js-tokens": "3.0.0"}),
+		},
+	}}
+	transitive := &Dependencies{Lockfiles: map[string]*Lockfile{
+		"yarn.lock": &Lockfile{
+			Current: current,
+			Updated: lockfileVersion(map[string]string{"react": "16.0.0", "loose-envify": "1.4.0", "object-assign": "4.1.1"}),
+		},
+	}}
+
+	for _, merged := range []*Dependencies{direct.Merge(transitive), transitive.Merge(direct)} {
+		updated := merged.Lockfiles["yarn.lock"].Updated.Dependencies
+		if len(updated) != 3 {
+			t.Errorf("unexpected dependencies %v", updated)
+		}
+		if v := updated["react"].Version.Name; v != "17.0.0" {
+			t.Errorf("direct update lost, got react %s", v)
+		}
+		if v := updated["loose-envify"].Version.Name; v != "1.4.0" {
+			t.Errorf("transitive update lost, got loose-envify %s", v)
+		}
+		if _, found := updated["object-assign"]; !found {
+			t.Error("added dependency lost")
+		}
+		if _, found := updated["js-tokens"]; found {
+			t.Error("removed dependency came back")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package schema
+
+import "github.com/dropseed/deps/pkg/versioning"
+
+// UpdateType classifies the change to a manifest dependency,
+// using the resolved versions if the component provided them
+func (manifest *Manifest) UpdateType(name string) versioning.UpdateType {
+	if manifest.Current == nil || manifest.Updated == nil {
+		return versioning.Unknown
+	}
+	current, found := manifest.Current.Dependencies[name]
+	if !found {
+		return versioning.Unknown
+	}
+	updated, found := manifest.Updated.Dependencies[name]
+	if !found {
+		return versioning.Unknown
+	}
+	if current.Version != nil && updated.Version != nil {
+		return versioning.Classify(current.Version.Name, updated.Version.Name)
+	}
+	return versioning.ClassifyConstraints(current.Constraint, updated.Constraint)
+}
+
+// UpdateType classifies the change to a lockfile dependency
+func (lockfile *Lockfile) UpdateType(name string) versioning.UpdateType {
+	if lockfile.Current == nil || lockfile.Updated == nil {
+		return versioning.Unknown
+	}
+	current, found := lockfile.Current.Dependencies[name]
+	if !found {
+		return versioning.Unknown
+	}
+	updated, found := lockfile.Updated.Dependencies[name]
+	if !found {
+		return versioning.Unknown
+	}
+	return versioning.Classify(current.Version.Name, updated.Version.Name)
+}
+
+// UpdateTypes classifies every changed dependency in the lockfile
+func (lockfile *Lockfile) UpdateTypes() map[string]versioning.UpdateType {
+	types := map[string]versioning.UpdateType{}
+	if !lockfile.HasUpdates() || lockfile.Current == nil {
+		return types
+	}
+	for name, updated := range lockfile.Updated.Dependencies {
+		if current, found := lockfile.Current.Dependencies[name]; found && current.Version.Name != updated.Version.Name {
+			types[name] = lockfile.UpdateType(name)
+		}
+	}
+	return types
+}
+
+// UpdateType returns the largest change across all manifests and lockfiles
+func (s *Dependencies) UpdateType() versioning.UpdateType {
+	types := []versioning.UpdateType{}
+
+	for _, manifest := range s.Manifests {
+		if !manifest.HasUpdates() {
+			continue
+		}
+		for name := range manifest.Updated.Dependencies {
+			types = append(types, manifest.UpdateType(name))
+		}
+	}
+
+	for _, lockfile := range s.Lockfiles {
+		for _, t := range lockfile.UpdateTypes() {
+			types = append(types, t)
+		}
+	}
+
+	return versioning.Largest(types...)
+}
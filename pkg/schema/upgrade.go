@@ -0,0 +1,77 @@
+package schema
+
+// CurrentSchemaVersion is the version of the data that this package reads and writes.
+// Data without a "schema_version" is treated as version 1.
+const CurrentSchemaVersion = 3
+
+// upgrades convert raw JSON data from the version (key) to the next version
+var upgrades = map[int]func(map[string]interface{}){
+	1: upgradeFromVersion1,
+	// Version 3 only added optional version metadata
+	2: func(data map[string]interface{}) {},
+}
+
+func schemaVersionFromData(data map[string]interface{}) (int, error) {
+	raw, found := data["schema_version"]
+	if !found || raw == nil {
+		return 1, nil
+	}
+
+	version, ok := raw.(float64)
+	if !ok || version != float64(int(version)) || version < 1 {
+		return 0, newValidationError("schema_version", "expected a positive integer")
+	}
+
+	return int(version), nil
+}
+
+func upgradeData(data map[string]interface{}, from int) error {
+	for version := from; version < CurrentSchemaVersion; version++ {
+		upgrade, found := upgrades[version]
+		if !found {
+			return newValidationError("schema_version", "unable to upgrade from version %d", version)
+		}
+		upgrade(data)
+	}
+	return nil
+}
+
+// Version 1 lockfile dependencies could use "installed" instead of "version",
+// and manifest dependencies listed "available" versions which are no longer used
+func upgradeFromVersion1(data map[string]interface{}) {
+	for _, lockfile := range objectValues(data["lockfiles"]) {
+		for _, key := range []string{"current", "updated"} {
+			lockfileVersion, _ := lockfile[key].(map[string]interface{})
+			for _, dependency := range objectValues(lockfileVersion["dependencies"]) {
+				if installed, found := dependency["installed"]; found {
+					if _, hasVersion := dependency["version"]; !hasVersion {
+						dependency["version"] = installed
+					}
+					delete(dependency, "installed")
+				}
+			}
+		}
+	}
+
+	for _, manifest := range objectValues(data["manifests"]) {
+		for _, key := range []string{"current", "updated"} {
+			manifestVersion, _ := manifest[key].(map[string]interface{})
+			for _, dependency := range objectValues(manifestVersion["dependencies"]) {
+				delete(dependency, "available")
+			}
+		}
+	}
+}
+
+// objectValues returns the values of a JSON object that are objects themselves
+func objectValues(i interface{}) []map[string]interface{} {
+	values := []map[string]interface{}{}
+	if obj, ok := i.(map[string]interface{}); ok {
+		for _, v := range obj {
+			if child, ok := v.(map[string]interface{}); ok {
+				values = append(values, child)
+			}
+		}
+	}
+	return values
+}
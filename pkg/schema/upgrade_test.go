@@ -0,0 +1,81 @@
+package schema
+
+import "testing"
+
+func TestUpgradeFromVersion1(t *testing.T) {
+	content := `{
+	"lockfiles": {
+		"yarn.lock": {
+			"current": {
+				"fingerprint": "abc",
+				"dependencies": {"react": {"source": "npm", "installed": {"name": "16.0.0"}}}
+			}
+		}
+	},
+	"manifests": {
+		"package.json": {
+			"current": {
+				"dependencies": {"react": {"source": "npm", "constraint": "^16.0.0", "available": [{"name": "17.0.0"}]}}
+			}
+		}
+	}
+}`
+	deps, err := NewDependenciesFromJSONContent([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deps.SchemaVersion != CurrentSchemaVersion {
+		t.Error("schema version not upgraded")
+	}
+	if deps.Lockfiles["yarn.lock"].Current.Dependencies["react"].Version.Name != "16.0.0" {
+		t.Error("installed was not converted to version")
+	}
+	if len(deps.Warnings) != 0 {
+		t.Error(deps.Warnings)
+	}
+}
+
+func TestNewerVersionUnknownFields(t *testing.T) {
+	content := `{
+	"schema_version": 99,
+	"manifests": {
+		"package.json": {
+			"current": {
+				"dependencies": {"react": {"source": "npm", "constraint": "^16.0.0", "future": true}}
+			}
+		}
+	}
+}`
+	deps, err := NewDependenciesFromJSONContent([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps.Warnings) != 2 {
+		t.Fatal(deps.Warnings)
+	}
+	if deps.Warnings[1] != `Ignoring unknown field manifests["package.json"].current.dependencies["react"].future` {
+		t.Error(deps.Warnings[1])
+	}
+}
+
+func TestCurrentVersionUnknownFields(t *testing.T) {
+	content := `{
+	"schema_version": 2,
+	"manifests": {
+		"package.json": {
+			"current": {
+				"dependencies": {"react": {"source": "npm", "constraint": "^16.0.0", "future": true}}
+			}
+		}
+	}
+}`
+	if _, err := NewDependenciesFromJSONContent([]byte(content)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestInvalidSchemaVersion(t *testing.T) {
+	if _, err := NewDependenciesFromJSONContent([]byte(`{"schema_version": "2"}`)); err == nil {
+		t.FailNow()
+	}
+}
@@ -1,15 +1,22 @@
 package schema
 
-import "errors"
+import "time"
 
 type Version struct {
 	Name string `json:"name"`
 	Link string `json:"link,omitempty"`
+	// Optional metadata about the release itself
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	Deprecated bool       `json:"deprecated,omitempty"`
+	Yanked     bool       `json:"yanked,omitempty"`
+	License    string     `json:"license,omitempty"`
+	// Advisories are the IDs of security advisories that affect this version (CVE, GHSA, etc.)
+	Advisories []string `json:"advisories,omitempty"`
 }
 
 func (v *Version) Validate() error {
 	if v.Name == "" {
-		return errors.New("dependency name is requried")
+		return newValidationError("name", "is required")
 	}
 	return nil
 }
@@ -0,0 +1,140 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Range is a set of version constraints, like ">=2.0.0 <3" or "4.17.99 || 5.x"
+type Range struct {
+	Original string
+	// any of the alternatives can match, but all of the comparators within one have to
+	alternatives [][]*comparator
+}
+
+type comparator struct {
+	operator string
+	version  *Version
+	wildcard bool
+}
+
+var comparatorPattern = regexp.MustCompile("^(<=|>=|<|>|==|=|!=)?\\s*(.+)$")
+var wildcardPattern = regexp.MustCompile("\\.[x*]$")
+var operatorSpacePattern = regexp.MustCompile("(<=|>=|<|>|==|=|!=)\\s+")
+
+// ParseRange parses a range string
+func ParseRange(s string) (*Range, error) {
+	r := &Range{
+		Original: s,
+	}
+
+	for _, alternative := range strings.Split(s, "||") {
+		comparators := []*comparator{}
+
+		// allow "> 1.0" as well as ">1.0"
+		normalized := operatorSpacePattern.ReplaceAllString(alternative, "$1")
+		fields := strings.FieldsFunc(normalized, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+
+		for _, field := range fields {
+			c, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range \"%s\": %v", s, err)
+			}
+			comparators = append(comparators, c)
+		}
+
+		if len(comparators) == 0 {
+			return nil, fmt.Errorf("invalid version range \"%s\"", s)
+		}
+
+		r.alternatives = append(r.alternatives, comparators)
+	}
+
+	return r, nil
+}
+
+func parseComparator(s string) (*comparator, error) {
+	match := comparatorPattern.FindStringSubmatch(s)
+	if match == nil {
+		return nil, fmt.Errorf("unable to parse \"%s\"", s)
+	}
+
+	c := &comparator{
+		operator: match[1],
+	}
+
+	versionString := match[2]
+	for wildcardPattern.MatchString(versionString) {
+		c.wildcard = true
+		versionString = versionString[:len(versionString)-2]
+	}
+
+	v, err := Parse(versionString)
+	if err != nil {
+		return nil, err
+	}
+	c.version = v
+
+	if c.wildcard && c.operator != "" && c.operator != "=" && c.operator != "==" && c.operator != "!=" {
+		return nil, fmt.Errorf("wildcards can't be used with %s", c.operator)
+	}
+
+	return c, nil
+}
+
+// Contains reports whether the version is in the range
+func (r *Range) Contains(v *Version) bool {
+	for _, comparators := range r.alternatives {
+		matched := true
+		for _, c := range comparators {
+			if !c.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *comparator) matches(v *Version) bool {
+	if c.wildcard {
+		prefix := true
+		for i, n := range c.version.Release {
+			if v.releasePart(i) != n {
+				prefix = false
+				break
+			}
+		}
+		if c.operator == "!=" {
+			return !prefix
+		}
+		return prefix
+	}
+
+	compared := Compare(v, c.version)
+
+	switch c.operator {
+	case "<":
+		return compared < 0
+	case "<=":
+		return compared <= 0
+	case ">":
+		return compared > 0
+	case ">=":
+		return compared >= 0
+	case "!=":
+		return compared != 0
+	}
+
+	return compared == 0
+}
+
+func (r *Range) String() string {
+	return r.Original
+}
@@ -0,0 +1,115 @@
+package versioning
+
+// UpdateType describes the size of a change from one version to another
+type UpdateType string
+
+const (
+	Prerelease UpdateType = "prerelease"
+	Patch      UpdateType = "patch"
+	Minor      UpdateType = "minor"
+	Major      UpdateType = "major"
+	Unknown    UpdateType = "unknown"
+)
+
+// UpdateTypes lists every type, from smallest to largest
+var UpdateTypes = []UpdateType{
+	Prerelease,
+	Patch,
+	Minor,
+	Major,
+	Unknown,
+}
+
+// IsValid reports whether s is the name of an UpdateType
+func IsValid(s string) bool {
+	for _, t := range UpdateTypes {
+		if string(t) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (t UpdateType) rank() int {
+	for i, ut := range UpdateTypes {
+		if ut == t {
+			return i
+		}
+	}
+	return len(UpdateTypes)
+}
+
+// Largest returns the biggest change in a set of update types.
+// Unknown is considered the largest since it can't be assumed safe.
+func Largest(types ...UpdateType) UpdateType {
+	largest := UpdateType("")
+	for _, t := range types {
+		if largest == "" || t.rank() > largest.rank() {
+			largest = t
+		}
+	}
+	if largest == "" {
+		return Unknown
+	}
+	return largest
+}
+
+// Classify compares two version strings
+func Classify(from, to string) UpdateType {
+	fromVersion, err := Parse(from)
+	if err != nil {
+		return Unknown
+	}
+	toVersion, err := Parse(to)
+	if err != nil {
+		return Unknown
+	}
+	return ClassifyVersions(fromVersion, toVersion)
+}
+
+// ClassifyConstraints compares the versions found in two constraints
+func ClassifyConstraints(from, to string) UpdateType {
+	fromVersion, err := VersionFromConstraint(from)
+	if err != nil {
+		return Unknown
+	}
+	toVersion, err := VersionFromConstraint(to)
+	if err != nil {
+		return Unknown
+	}
+	return ClassifyVersions(fromVersion, toVersion)
+}
+
+// ClassifyVersions returns the first part of the version that changed.
+// Changes that only affect the prerelease (1.0.0-beta.1 to 1.0.0-beta.2, or
+// 1.0.0-rc.1 to 1.0.0) are Prerelease, and post releases count as Patch.
+func ClassifyVersions(from, to *Version) UpdateType {
+	if from.Epoch != to.Epoch {
+		return Major
+	}
+	if from.releasePart(0) != to.releasePart(0) {
+		return Major
+	}
+	if from.releasePart(1) != to.releasePart(1) {
+		return Minor
+	}
+
+	length := len(from.Release)
+	if len(to.Release) > length {
+		length = len(to.Release)
+	}
+	for i := 2; i < length; i++ {
+		if from.releasePart(i) != to.releasePart(i) {
+			return Patch
+		}
+	}
+
+	if from.Prerelease != to.Prerelease {
+		return Prerelease
+	}
+	if from.Post != to.Post {
+		return Patch
+	}
+
+	return Unknown
+}
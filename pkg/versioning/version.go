@@ -0,0 +1,201 @@
+// Package versioning parses version strings from different ecosystems
+// (semver, PEP 440, and loosely numeric versions) so they can be compared
+package versioning
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version string
+type Version struct {
+	Original   string
+	Epoch      int
+	Release    []int
+	Prerelease string
+	Post       string
+}
+
+var epochPattern = regexp.MustCompile("^(\\d+)!")
+var releasePattern = regexp.MustCompile("^\\d+(\\.\\d+)*")
+var postPattern = regexp.MustCompile("^(?i)(post|rev|r)\\.?\\d*$")
+
+// Parse a version string like "1.2.3", "v2.0.0-beta.1", "1!2.0rc1" or "2020.01"
+func Parse(s string) (*Version, error) {
+	v := &Version{
+		Original: s,
+	}
+
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "vV=")
+
+	// build metadata never affects precedence
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+
+	if match := epochPattern.FindStringSubmatch(s); match != nil {
+		epoch, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+		v.Epoch = epoch
+		s = s[len(match[0]):]
+	}
+
+	release := releasePattern.FindString(s)
+	if release == "" {
+		return nil, fmt.Errorf("unable to parse version \"%s\"", v.Original)
+	}
+	for _, part := range strings.Split(release, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		v.Release = append(v.Release, n)
+	}
+
+	rest := strings.TrimLeft(s[len(release):], "-_.")
+	if rest == "" {
+		return v, nil
+	}
+
+	if postPattern.MatchString(rest) {
+		v.Post = strings.ToLower(rest)
+	} else {
+		v.Prerelease = strings.ToLower(rest)
+	}
+
+	return v, nil
+}
+
+// IsPrerelease reports whether this is an alpha, beta, rc, dev, etc. release
+func (v *Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+func (v *Version) String() string {
+	return v.Original
+}
+
+// releasePart returns the release number at index i, with missing parts as 0
+func (v *Version) releasePart(i int) int {
+	if i < len(v.Release) {
+		return v.Release[i]
+	}
+	return 0
+}
+
+// Compare returns -1, 0 or 1 if a is less than, equal to, or greater than b
+func Compare(a, b *Version) int {
+	if a.Epoch != b.Epoch {
+		return compareInts(a.Epoch, b.Epoch)
+	}
+
+	length := len(a.Release)
+	if len(b.Release) > length {
+		length = len(b.Release)
+	}
+	for i := 0; i < length; i++ {
+		if c := compareInts(a.releasePart(i), b.releasePart(i)); c != 0 {
+			return c
+		}
+	}
+
+	// A prerelease comes before the release itself
+	if a.Prerelease != b.Prerelease {
+		if a.Prerelease == "" {
+			return 1
+		}
+		if b.Prerelease == "" {
+			return -1
+		}
+		return compareIdentifiers(a.Prerelease, b.Prerelease)
+	}
+
+	// A post release comes after the release itself
+	if a.Post != b.Post {
+		if a.Post == "" {
+			return -1
+		}
+		if b.Post == "" {
+			return 1
+		}
+		return compareIdentifiers(a.Post, b.Post)
+	}
+
+	return 0
+}
+
+var identifierPattern = regexp.MustCompile("[0-9]+|[a-z]+")
+
+// compareIdentifiers compares prerelease strings piece by piece,
+// numerically where both pieces are numbers
+func compareIdentifiers(a, b string) int {
+	aParts := identifierPattern.FindAllString(a, -1)
+	bParts := identifierPattern.FindAllString(b, -1)
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		if aErr == nil && bErr == nil {
+			if c := compareInts(aNum, bNum); c != 0 {
+				return c
+			}
+			continue
+		}
+		if aErr == nil {
+			// numbers sort before words
+			return -1
+		}
+		if bErr == nil {
+			return 1
+		}
+		if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(len(aParts), len(bParts))
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+var constraintVersionPattern = regexp.MustCompile("(<=|>=|<|>|===|==|=|~=|~>|\\^|~|!=)?\\s*v?(\\d+(\\.\\d+)*(-[0-9A-Za-z.-]+|[a-zA-Z]+\\.?\\d*)?)")
+
+// VersionFromConstraint finds the most relevant version in a constraint
+// like "^1.2.0" or ">=2.0,<3.0" (the highest version that isn't an upper bound)
+func VersionFromConstraint(constraint string) (*Version, error) {
+	var found *Version
+
+	for _, match := range constraintVersionPattern.FindAllStringSubmatch(constraint, -1) {
+		operator := match[1]
+		if operator == "<" || operator == "<=" || operator == "!=" {
+			continue
+		}
+		v, err := Parse(match[2])
+		if err != nil {
+			continue
+		}
+		if found == nil || Compare(v, found) > 0 {
+			found = v
+		}
+	}
+
+	if found == nil {
+		return nil, errors.New("no version found in constraint")
+	}
+
+	return found, nil
+}
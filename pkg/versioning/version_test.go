@@ -0,0 +1,149 @@
+package versioning
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input      string
+		epoch      int
+		release    int
+		prerelease string
+		post       string
+	}{
+		{input: "1.2.3", release: 3},
+		{input: "v1.2.3-beta.1+build.5", release: 3, prerelease: "beta.1"},
+		{input: "1!2.0rc1", epoch: 1, release: 2, prerelease: "rc1"},
+		{input: "1.0.post2", release: 2, post: "post2"},
+		{input: "2020.01.05.1", release: 4},
+		{input: "3", release: 1},
+	}
+
+	for _, test := range tests {
+		v, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("%s: %v", test.input, err)
+			continue
+		}
+		if v.Epoch != test.epoch || len(v.Release) != test.release || v.Prerelease != test.prerelease || v.Post != test.post {
+			t.Errorf("%s parsed as %+v", test.input, v)
+		}
+	}
+
+	if _, err := Parse("latest"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.post1", "1.0", 1},
+		{"1!1.0", "2.0", 1},
+	}
+
+	for _, test := range tests {
+		a, _ := Parse(test.a)
+		b, _ := Parse(test.b)
+		if c := Compare(a, b); c != test.expected {
+			t.Errorf("%s <=> %s = %d", test.a, test.b, c)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected UpdateType
+	}{
+		{"1.2.3", "2.0.0", Major},
+		{"1.2.3", "1.3.0", Minor},
+		{"1.2.3", "1.2.4", Patch},
+		{"1.2.3.4", "1.2.3.5", Patch},
+		{"2.0.0-beta.1", "2.0.0-beta.2", Prerelease},
+		{"2.0.0rc1", "2.0.0", Prerelease},
+		{"1.2.3", "2.0.0-beta.1", Major},
+		{"1.0", "1.0.post1", Patch},
+		{"1.0", "latest", Unknown},
+	}
+
+	for _, test := range tests {
+		if actual := Classify(test.from, test.to); actual != test.expected {
+			t.Errorf("%s -> %s = %s", test.from, test.to, actual)
+		}
+	}
+}
+
+func TestClassifyConstraints(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected UpdateType
+	}{
+		{"^16.8.0", "^17.0.1", Major},
+		{"~> 2.1", "~> 2.2", Minor},
+		{">=1.0,<2.0", ">=1.0.1,<2.0", Patch},
+		{"==2.2.0", "==3.0.0", Major},
+		{"*", "^1.0.0", Unknown},
+	}
+
+	for _, test := range tests {
+		if actual := ClassifyConstraints(test.from, test.to); actual != test.expected {
+			t.Errorf("%s -> %s = %s", test.from, test.to, actual)
+		}
+	}
+}
+
+func TestLargest(t *testing.T) {
+	if Largest(Patch, Major, Minor) != Major {
+		t.Fail()
+	}
+	if Largest(Patch, Unknown) != Unknown {
+		t.Fail()
+	}
+	if Largest() != Unknown {
+		t.Fail()
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		r        string
+		version  string
+		expected bool
+	}{
+		{"4.17.99", "4.17.99", true},
+		{"4.17.99", "4.17.100", false},
+		{">=3.0.0", "3.1.0", true},
+		{">=3.0.0 <4", "4.0.0", false},
+		{">= 3.0.0, < 4", "3.9.9", true},
+		{"5.x", "5.2.1", true},
+		{"5.x", "6.0.0", false},
+		{"1.0.0 || >=3", "3.0.0", true},
+		{"!=2.0.0", "2.0.0", false},
+	}
+
+	for _, test := range tests {
+		r, err := ParseRange(test.r)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		v, _ := Parse(test.version)
+		if r.Contains(v) != test.expected {
+			t.Errorf("%s contains %s != %t", test.r, test.version, test.expected)
+		}
+	}
+
+	if _, err := ParseRange(">=foo"); err == nil {
+		t.Error("expected an error")
+	}
+}